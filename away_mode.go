@@ -0,0 +1,187 @@
+package tapo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// AwayRule makes the device switch on and off at random between StartMinute and EndMinute,
+// both minutes of the day, to make the home look occupied. The device calls these anti-theft rules.
+type AwayRule struct {
+	Id          string       `json:"id,omitempty"`
+	Enable      bool         `json:"enable"`
+	Mode        ScheduleMode `json:"mode"`
+	StartType   string       `json:"s_type"`
+	StartMinute int          `json:"s_min"`
+	EndType     string       `json:"e_type"`
+	EndMinute   int          `json:"e_min"`
+	WeekDays    WeekdayMask  `json:"week_day"`
+	Day         int          `json:"day,omitempty"`
+	Month       int          `json:"month,omitempty"`
+	Year        int          `json:"year,omitempty"`
+}
+
+type awayRulesPage struct {
+	Enable                bool       `json:"enable"`
+	RuleList              []AwayRule `json:"rule_list"`
+	AntitheftRuleMaxCount int        `json:"antitheft_rule_max_count"`
+	StartIndex            int        `json:"start_index"`
+	Sum                   int        `json:"sum"`
+}
+
+// AwayRules are the away rules stored on a device. Enable is the device's away mode switch,
+// which turns all rules on or off without editing them.
+type AwayRules struct {
+	Enable   bool
+	MaxCount int
+	Rules    []AwayRule
+}
+
+// AwayModeDevice is a device that supports away mode, such as SmartPlug and SmartBulb.
+type AwayModeDevice interface {
+	SetAwayModeEnabled(ctx context.Context, enabled bool) error
+}
+
+// NewAwayRule returns a rule that is active from start to end, given as hour and minute, on the given weekdays.
+func NewAwayRule(startHour, startMinute, endHour, endMinute int, days WeekdayMask) AwayRule {
+	return AwayRule{
+		Enable:      true,
+		Mode:        ScheduleModeRepeat,
+		StartType:   "normal",
+		StartMinute: startHour*60 + startMinute,
+		EndType:     "normal",
+		EndMinute:   endHour*60 + endMinute,
+		WeekDays:    days,
+	}
+}
+
+// Validate checks the rule against the limits the device accepts.
+func (r AwayRule) Validate() error {
+	if r.StartMinute < 0 || r.StartMinute >= 24*60 || r.EndMinute < 0 || r.EndMinute >= 24*60 {
+		return fmt.Errorf("away window %d-%d is outside 0-1439", r.StartMinute, r.EndMinute)
+	}
+	if r.StartMinute == r.EndMinute {
+		return errors.New("away window must not be empty")
+	}
+	switch r.Mode {
+	case ScheduleModeRepeat:
+		if r.WeekDays == 0 || r.WeekDays > EveryDay {
+			return fmt.Errorf("invalid weekday mask: %#x", r.WeekDays)
+		}
+	case ScheduleModeOnce:
+		if r.Year == 0 || r.Month == 0 || r.Day == 0 {
+			return fmt.Errorf("a rule that runs once needs a date")
+		}
+	default:
+		return fmt.Errorf("unsupported schedule mode: %q", r.Mode)
+	}
+	return nil
+}
+
+// SetAwayMode enables or disables away mode on every given device, for example to start
+// vacation mode for the whole house. Every device is switched even if some fail;
+// the errors of the failed devices are returned together.
+func SetAwayMode(ctx context.Context, enabled bool, devices ...AwayModeDevice) error {
+	var errs []error
+	for i, device := range devices {
+		if err := device.SetAwayModeEnabled(ctx, enabled); err != nil {
+			errs = append(errs, fmt.Errorf("device %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (d *Device) getAwayRules(ctx context.Context) (*AwayRules, error) {
+	rules := &AwayRules{Rules: make([]AwayRule, 0)}
+	for {
+		var page awayRulesPage
+		params := map[string]int{"start_index": len(rules.Rules)}
+		if err := d.executeSingleMethod(ctx, "get_antitheft_rules", params, &page); err != nil {
+			return nil, err
+		}
+		rules.Enable = page.Enable
+		rules.MaxCount = page.AntitheftRuleMaxCount
+		rules.Rules = append(rules.Rules, page.RuleList...)
+		if len(page.RuleList) == 0 || len(rules.Rules) >= page.Sum {
+			return rules, nil
+		}
+	}
+}
+
+func (d *Device) addAwayRule(ctx context.Context, rule AwayRule) (string, error) {
+	if err := rule.Validate(); err != nil {
+		return "", err
+	}
+	rule.Id = ""
+	var response struct {
+		Id string `json:"id"`
+	}
+	if err := d.executeSingleMethod(ctx, "add_antitheft_rule", rule, &response); err != nil {
+		return "", err
+	}
+	return response.Id, nil
+}
+
+func (d *Device) editAwayRule(ctx context.Context, rule AwayRule) error {
+	if rule.Id == "" {
+		return errors.New("rule id must not be empty")
+	}
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	return d.executeSingleMethod(ctx, "edit_antitheft_rule", rule, nil)
+}
+
+// setAwayModeEnabled switches the device's away mode, leaving the rules themselves unchanged.
+func (d *Device) setAwayModeEnabled(ctx context.Context, enabled bool) error {
+	return d.executeSingleMethod(ctx, "edit_antitheft_rule", map[string]bool{"enable": enabled}, nil)
+}
+
+// GetAwayRules returns every away rule stored on the plug and whether away mode is enabled.
+func (t *SmartPlug) GetAwayRules(ctx context.Context) (*AwayRules, error) {
+	return t.getAwayRules(ctx)
+}
+
+// AddAwayRule stores a new away rule on the plug and returns its id.
+func (t *SmartPlug) AddAwayRule(ctx context.Context, rule AwayRule) (string, error) {
+	return t.addAwayRule(ctx, rule)
+}
+
+func (t *SmartPlug) EditAwayRule(ctx context.Context, rule AwayRule) error {
+	return t.editAwayRule(ctx, rule)
+}
+
+// RemoveAwayRules removes the away rules with the given ids, or all of them if no ids are given.
+func (t *SmartPlug) RemoveAwayRules(ctx context.Context, ids ...string) error {
+	return t.removeRules(ctx, "remove_antitheft_rules", ids)
+}
+
+// SetAwayModeEnabled switches the plug's away mode on or off.
+func (t *SmartPlug) SetAwayModeEnabled(ctx context.Context, enabled bool) error {
+	return t.setAwayModeEnabled(ctx, enabled)
+}
+
+// GetAwayRules returns every away rule stored on the light and whether away mode is enabled.
+func (b *SmartBulb) GetAwayRules(ctx context.Context) (*AwayRules, error) {
+	return b.getAwayRules(ctx)
+}
+
+// AddAwayRule stores a new away rule on the light and returns its id.
+func (b *SmartBulb) AddAwayRule(ctx context.Context, rule AwayRule) (string, error) {
+	return b.addAwayRule(ctx, rule)
+}
+
+func (b *SmartBulb) EditAwayRule(ctx context.Context, rule AwayRule) error {
+	return b.editAwayRule(ctx, rule)
+}
+
+// RemoveAwayRules removes the away rules with the given ids, or all of them if no ids are given.
+func (b *SmartBulb) RemoveAwayRules(ctx context.Context, ids ...string) error {
+	return b.removeRules(ctx, "remove_antitheft_rules", ids)
+}
+
+// SetAwayModeEnabled switches the light's away mode on or off.
+func (b *SmartBulb) SetAwayModeEnabled(ctx context.Context, enabled bool) error {
+	return b.setAwayModeEnabled(ctx, enabled)
+}
@@ -0,0 +1,146 @@
+package tapo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Camera is a C-series camera such as the C200, C210 or C225. Cameras use the SSL AES transport
+// and the same camelCase multipleRequest method set as the H200 hub.
+type Camera struct {
+	*Device
+}
+
+func NewCamera(ctx context.Context, host, email, password string, options Options) (*Camera, error) {
+	tr, err := NewSslAesTransport(ctx, host, email, password, options)
+	if err != nil {
+		return nil, err
+	}
+	tapo := NewDevice(tr, options)
+	return &Camera{tapo}, nil
+}
+
+// Preset is a saved pan/tilt position. Pan and Tilt are in the camera's normalized coordinates.
+type Preset struct {
+	Id       string
+	Name     string
+	Pan      float64
+	Tilt     float64
+	ReadOnly bool
+}
+
+type presetConfig struct {
+	Preset struct {
+		Preset struct {
+			Id           []string `json:"id"`
+			Name         []string `json:"name"`
+			PositionPan  []string `json:"position_pan"`
+			PositionTilt []string `json:"position_tilt"`
+			ReadOnly     []string `json:"read_only"`
+		} `json:"preset"`
+	} `json:"preset"`
+}
+
+type motorParams struct {
+	Motor any `json:"motor"`
+}
+
+type presetParams struct {
+	Preset any `json:"preset"`
+}
+
+type coordinates struct {
+	X string `json:"x_coord"`
+	Y string `json:"y_coord"`
+}
+
+// Move pans and tilts the camera by the given number of degrees relative to its current position.
+func (c *Camera) Move(ctx context.Context, pan, tilt int) error {
+	params := motorParams{Motor: map[string]coordinates{
+		"move": {X: strconv.Itoa(pan), Y: strconv.Itoa(tilt)},
+	}}
+	return c.executeMultipleRequest(ctx, "motorMove", params, nil)
+}
+
+// MoveTo moves the camera to an absolute position in the normalized coordinates presets are stored in.
+func (c *Camera) MoveTo(ctx context.Context, pan, tilt float64) error {
+	if pan < -1 || pan > 1 || tilt < -1 || tilt > 1 {
+		return fmt.Errorf("position %.2f, %.2f is outside -1 to 1", pan, tilt)
+	}
+	params := motorParams{Motor: map[string]coordinates{
+		"move_to": {X: strconv.FormatFloat(pan, 'f', -1, 64), Y: strconv.FormatFloat(tilt, 'f', -1, 64)},
+	}}
+	return c.executeMultipleRequest(ctx, "motorMoveTo", params, nil)
+}
+
+// MoveStep moves the camera one step in the direction given as an angle in degrees,
+// where 0 is right, 90 is up, 180 is left and 270 is down.
+func (c *Camera) MoveStep(ctx context.Context, direction int) error {
+	if direction < 0 || direction >= 360 {
+		return fmt.Errorf("direction %d is outside 0-359", direction)
+	}
+	params := motorParams{Motor: map[string]map[string]string{
+		"movestep": {"direction": strconv.Itoa(direction)},
+	}}
+	return c.executeMultipleRequest(ctx, "motorMoveStep", params, nil)
+}
+
+// Calibrate runs the motor calibration, moving the camera through its full range and back to home.
+func (c *Camera) Calibrate(ctx context.Context) error {
+	params := motorParams{Motor: map[string]string{"manual_cali": ""}}
+	return c.executeMultipleRequest(ctx, "motorCalibrate", params, nil)
+}
+
+func (c *Camera) GetPresets(ctx context.Context) ([]Preset, error) {
+	var response presetConfig
+	params := presetParams{Preset: map[string][]string{"name": {"preset"}}}
+	if err := c.executeMultipleRequest(ctx, "getPresetConfig", params, &response); err != nil {
+		return nil, err
+	}
+	config := response.Preset.Preset
+	presets := make([]Preset, 0, len(config.Id))
+	for i, id := range config.Id {
+		preset := Preset{Id: id}
+		if i < len(config.Name) {
+			preset.Name = config.Name[i]
+		}
+		if i < len(config.PositionPan) {
+			preset.Pan, _ = strconv.ParseFloat(config.PositionPan[i], 64)
+		}
+		if i < len(config.PositionTilt) {
+			preset.Tilt, _ = strconv.ParseFloat(config.PositionTilt[i], 64)
+		}
+		if i < len(config.ReadOnly) {
+			preset.ReadOnly = config.ReadOnly[i] == "1"
+		}
+		presets = append(presets, preset)
+	}
+	return presets, nil
+}
+
+// SavePreset saves the current position of the camera as a preset with the given name.
+func (c *Camera) SavePreset(ctx context.Context, name string) error {
+	if name == "" {
+		return fmt.Errorf("preset name must not be empty")
+	}
+	params := presetParams{Preset: map[string]map[string]string{
+		"set_preset": {"name": name, "save_ptz": "1"},
+	}}
+	// The misspelling is part of the camera API.
+	return c.executeMultipleRequest(ctx, "addMotorPostion", params, nil)
+}
+
+func (c *Camera) DeletePreset(ctx context.Context, id string) error {
+	params := presetParams{Preset: map[string]map[string][]string{
+		"remove_preset": {"id": {id}},
+	}}
+	return c.executeMultipleRequest(ctx, "deletePreset", params, nil)
+}
+
+func (c *Camera) GoToPreset(ctx context.Context, id string) error {
+	params := presetParams{Preset: map[string]map[string]string{
+		"goto_preset": {"id": id},
+	}}
+	return c.executeMultipleRequest(ctx, "motorMoveToPreset", params, nil)
+}
@@ -0,0 +1,249 @@
+package tapo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// DetectionSensitivity is the sensitivity of a camera detection feature.
+type DetectionSensitivity string
+
+const (
+	DetectionSensitivityLow    DetectionSensitivity = "low"
+	DetectionSensitivityMedium DetectionSensitivity = "medium"
+	DetectionSensitivityHigh   DetectionSensitivity = "high"
+)
+
+// Motion, person and pet detection store their sensitivity as a percentage.
+var detectionSensitivityLevels = map[DetectionSensitivity]string{
+	DetectionSensitivityLow:    "20",
+	DetectionSensitivityMedium: "50",
+	DetectionSensitivityHigh:   "80",
+}
+
+const (
+	cameraOn  = "on"
+	cameraOff = "off"
+)
+
+// DetectionConfig is the state of a camera detection feature such as motion or person detection.
+type DetectionConfig struct {
+	Enabled     bool
+	Sensitivity DetectionSensitivity
+}
+
+// AlarmConfig controls how the camera alerts locally when a detection triggers.
+type AlarmConfig struct {
+	Enabled bool
+	Sound   bool
+	Light   bool
+}
+
+// NotificationConfig controls the push notifications the camera sends to the Tapo app.
+type NotificationConfig struct {
+	Enabled     bool
+	RichEnabled bool
+}
+
+type detectionSection struct {
+	Enabled            string `json:"enabled,omitempty"`
+	Sensitivity        string `json:"sensitivity,omitempty"`
+	DigitalSensitivity string `json:"digital_sensitivity,omitempty"`
+}
+
+type alarmSection struct {
+	Enabled   string   `json:"enabled,omitempty"`
+	AlarmMode []string `json:"alarm_mode"`
+}
+
+type notificationSection struct {
+	NotificationEnabled     string `json:"notification_enabled,omitempty"`
+	RichNotificationEnabled string `json:"rich_notification_enabled,omitempty"`
+}
+
+// getConfig reads a single section of a camera configuration module, such as lens_mask_info of lens_mask.
+func (c *Camera) getConfig(ctx context.Context, method, module, section string, result any) error {
+	params := map[string]map[string][]string{module: {"name": {section}}}
+	var response map[string]map[string]json.RawMessage
+	if err := c.executeMultipleRequest(ctx, method, params, &response); err != nil {
+		return err
+	}
+	raw, ok := response[module][section]
+	if !ok {
+		return fmt.Errorf("%s returned no %s.%s", method, module, section)
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// setConfig writes a single section of a camera configuration module.
+func (c *Camera) setConfig(ctx context.Context, method, module, section string, value any) error {
+	params := map[string]map[string]any{module: {section: value}}
+	return c.executeMultipleRequest(ctx, method, params, nil)
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return cameraOn
+	}
+	return cameraOff
+}
+
+// IsPrivacyModeEnabled reports whether the lens mask is on, which stops the camera from recording.
+func (c *Camera) IsPrivacyModeEnabled(ctx context.Context) (bool, error) {
+	var section detectionSection
+	if err := c.getConfig(ctx, "getLensMaskConfig", "lens_mask", "lens_mask_info", &section); err != nil {
+		return false, err
+	}
+	return section.Enabled == cameraOn, nil
+}
+
+func (c *Camera) SetPrivacyMode(ctx context.Context, enabled bool) error {
+	section := detectionSection{Enabled: onOff(enabled)}
+	return c.setConfig(ctx, "setLensMaskConfig", "lens_mask", "lens_mask_info", section)
+}
+
+func (c *Camera) GetMotionDetection(ctx context.Context) (*DetectionConfig, error) {
+	var section detectionSection
+	if err := c.getConfig(ctx, "getDetectionConfig", "motion_detection", "motion_det", &section); err != nil {
+		return nil, err
+	}
+	return &DetectionConfig{
+		Enabled:     section.Enabled == cameraOn,
+		Sensitivity: sensitivityFromLevel(section.DigitalSensitivity),
+	}, nil
+}
+
+func (c *Camera) SetMotionDetection(ctx context.Context, config DetectionConfig) error {
+	level, err := sensitivityLevel(config.Sensitivity)
+	if err != nil {
+		return err
+	}
+	section := detectionSection{Enabled: onOff(config.Enabled), DigitalSensitivity: level}
+	return c.setConfig(ctx, "setDetectionConfig", "motion_detection", "motion_det", section)
+}
+
+func (c *Camera) GetPersonDetection(ctx context.Context) (*DetectionConfig, error) {
+	return c.getLevelDetection(ctx, "getPersonDetectionConfig", "people_detection")
+}
+
+func (c *Camera) SetPersonDetection(ctx context.Context, config DetectionConfig) error {
+	return c.setLevelDetection(ctx, "setPersonDetectionConfig", "people_detection", config)
+}
+
+func (c *Camera) GetPetDetection(ctx context.Context) (*DetectionConfig, error) {
+	return c.getLevelDetection(ctx, "getPetDetectionConfig", "pet_detection")
+}
+
+func (c *Camera) SetPetDetection(ctx context.Context, config DetectionConfig) error {
+	return c.setLevelDetection(ctx, "setPetDetectionConfig", "pet_detection", config)
+}
+
+// GetBabyCryDetection returns the baby cry detection state. Unlike the other detections,
+// the camera stores its sensitivity by name.
+func (c *Camera) GetBabyCryDetection(ctx context.Context) (*DetectionConfig, error) {
+	var section detectionSection
+	if err := c.getConfig(ctx, "getBCDConfig", "sound_detection", "bcd", &section); err != nil {
+		return nil, err
+	}
+	return &DetectionConfig{
+		Enabled:     section.Enabled == cameraOn,
+		Sensitivity: DetectionSensitivity(section.Sensitivity),
+	}, nil
+}
+
+func (c *Camera) SetBabyCryDetection(ctx context.Context, config DetectionConfig) error {
+	if _, err := sensitivityLevel(config.Sensitivity); err != nil {
+		return err
+	}
+	section := detectionSection{Enabled: onOff(config.Enabled), Sensitivity: string(config.Sensitivity)}
+	return c.setConfig(ctx, "setBCDConfig", "sound_detection", "bcd", section)
+}
+
+// GetAlarm returns whether the camera sounds its siren and flashes its light when a detection triggers.
+func (c *Camera) GetAlarm(ctx context.Context) (*AlarmConfig, error) {
+	var section alarmSection
+	if err := c.getConfig(ctx, "getAlarmConfig", "msg_alarm", "chn1_msg_alarm_info", &section); err != nil {
+		return nil, err
+	}
+	return &AlarmConfig{
+		Enabled: section.Enabled == cameraOn,
+		Sound:   slices.Contains(section.AlarmMode, "sound"),
+		Light:   slices.Contains(section.AlarmMode, "light"),
+	}, nil
+}
+
+func (c *Camera) SetAlarm(ctx context.Context, config AlarmConfig) error {
+	section := alarmSection{Enabled: onOff(config.Enabled), AlarmMode: make([]string, 0, 2)}
+	if config.Sound {
+		section.AlarmMode = append(section.AlarmMode, "sound")
+	}
+	if config.Light {
+		section.AlarmMode = append(section.AlarmMode, "light")
+	}
+	return c.setConfig(ctx, "setAlarmConfig", "msg_alarm", "chn1_msg_alarm_info", section)
+}
+
+func (c *Camera) GetNotifications(ctx context.Context) (*NotificationConfig, error) {
+	var section notificationSection
+	if err := c.getConfig(ctx, "getMsgPushConfig", "msg_push", "chn1_msg_push_info", &section); err != nil {
+		return nil, err
+	}
+	return &NotificationConfig{
+		Enabled:     section.NotificationEnabled == cameraOn,
+		RichEnabled: section.RichNotificationEnabled == cameraOn,
+	}, nil
+}
+
+func (c *Camera) SetNotifications(ctx context.Context, config NotificationConfig) error {
+	section := notificationSection{
+		NotificationEnabled:     onOff(config.Enabled),
+		RichNotificationEnabled: onOff(config.RichEnabled),
+	}
+	return c.setConfig(ctx, "setMsgPushConfig", "msg_push", "chn1_msg_push_info", section)
+}
+
+func (c *Camera) getLevelDetection(ctx context.Context, method, module string) (*DetectionConfig, error) {
+	var section detectionSection
+	if err := c.getConfig(ctx, method, module, "detection", &section); err != nil {
+		return nil, err
+	}
+	return &DetectionConfig{
+		Enabled:     section.Enabled == cameraOn,
+		Sensitivity: sensitivityFromLevel(section.Sensitivity),
+	}, nil
+}
+
+func (c *Camera) setLevelDetection(ctx context.Context, method, module string, config DetectionConfig) error {
+	level, err := sensitivityLevel(config.Sensitivity)
+	if err != nil {
+		return err
+	}
+	section := detectionSection{Enabled: onOff(config.Enabled), Sensitivity: level}
+	return c.setConfig(ctx, method, module, "detection", section)
+}
+
+func sensitivityLevel(sensitivity DetectionSensitivity) (string, error) {
+	level, ok := detectionSensitivityLevels[sensitivity]
+	if !ok {
+		return "", fmt.Errorf("unsupported detection sensitivity: %q", sensitivity)
+	}
+	return level, nil
+}
+
+// sensitivityFromLevel maps a percentage stored by the camera to the nearest named sensitivity.
+func sensitivityFromLevel(level string) DetectionSensitivity {
+	var percent int
+	if _, err := fmt.Sscan(level, &percent); err != nil {
+		return DetectionSensitivity(level)
+	}
+	switch {
+	case percent < 35:
+		return DetectionSensitivityLow
+	case percent < 65:
+		return DetectionSensitivityMedium
+	default:
+		return DetectionSensitivityHigh
+	}
+}
@@ -0,0 +1,240 @@
+package tapo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// NightVisionMode is how the camera lights the scene in the dark.
+type NightVisionMode string
+
+const (
+	NightVisionInfrared  NightVisionMode = "inf_night_vision"
+	NightVisionFullColor NightVisionMode = "wtl_night_vision"
+	NightVisionSmart     NightVisionMode = "md_night_vision"
+)
+
+// Names of the settings reported by DiffCameraSettings and ApplySettings.
+const (
+	SettingNightVisionMode          = "night_vision_mode"
+	SettingFlipImage                = "flip_image"
+	SettingLensDistortionCorrection = "lens_distortion_correction"
+	SettingStatusLed                = "status_led"
+	SettingVideoQuality             = "video_quality"
+	SettingTimeZone                 = "time_zone"
+)
+
+// CameraSettings holds the image and hardware settings of a camera.
+// Resolution is given as width*height, for example 1920*1080, and Bitrate in kbps.
+// TimeZoneId is an IANA zone such as Europe/London and TimeZone its offset, such as UTC+00:00.
+type CameraSettings struct {
+	NightVisionMode          NightVisionMode
+	FlipImage                bool
+	LensDistortionCorrection bool
+	StatusLed                bool
+	Resolution               string
+	Bitrate                  int
+	TimeZoneId               string
+	TimeZone                 string
+}
+
+type imageSwitchSection struct {
+	FlipType        string `json:"flip_type,omitempty"`
+	Ldc             string `json:"ldc,omitempty"`
+	NightVisionMode string `json:"night_vision_mode,omitempty"`
+}
+
+type videoSection struct {
+	Resolution string `json:"resolution,omitempty"`
+	Bitrate    string `json:"bitrate,omitempty"`
+}
+
+type timezoneSection struct {
+	TimingMode string `json:"timing_mode,omitempty"`
+	ZoneId     string `json:"zone_id,omitempty"`
+	Timezone   string `json:"timezone,omitempty"`
+}
+
+type ledSection struct {
+	Enabled string `json:"enabled"`
+}
+
+func (c *Camera) GetNightVisionMode(ctx context.Context) (NightVisionMode, error) {
+	var section imageSwitchSection
+	if err := c.getConfig(ctx, "getNightVisionModeConfig", "image", "switch", &section); err != nil {
+		return "", err
+	}
+	return NightVisionMode(section.NightVisionMode), nil
+}
+
+func (c *Camera) SetNightVisionMode(ctx context.Context, mode NightVisionMode) error {
+	switch mode {
+	case NightVisionInfrared, NightVisionFullColor, NightVisionSmart:
+	default:
+		return fmt.Errorf("unsupported night vision mode: %q", mode)
+	}
+	section := imageSwitchSection{NightVisionMode: string(mode)}
+	return c.setConfig(ctx, "setNightVisionModeConfig", "image", "switch", section)
+}
+
+// IsImageFlipped reports whether the image is rotated by 180 degrees, as needed for ceiling mounts.
+func (c *Camera) IsImageFlipped(ctx context.Context) (bool, error) {
+	var section imageSwitchSection
+	if err := c.getConfig(ctx, "getRotationStatus", "image", "switch", &section); err != nil {
+		return false, err
+	}
+	return section.FlipType == "center", nil
+}
+
+func (c *Camera) SetImageFlip(ctx context.Context, flipped bool) error {
+	section := imageSwitchSection{FlipType: cameraOff}
+	if flipped {
+		section.FlipType = "center"
+	}
+	return c.setConfig(ctx, "setRotationStatus", "image", "switch", section)
+}
+
+func (c *Camera) IsLensDistortionCorrectionEnabled(ctx context.Context) (bool, error) {
+	var section imageSwitchSection
+	if err := c.getConfig(ctx, "getLdc", "image", "switch", &section); err != nil {
+		return false, err
+	}
+	return section.Ldc == cameraOn, nil
+}
+
+func (c *Camera) SetLensDistortionCorrection(ctx context.Context, enabled bool) error {
+	section := imageSwitchSection{Ldc: onOff(enabled)}
+	return c.setConfig(ctx, "setLdc", "image", "switch", section)
+}
+
+func (c *Camera) IsStatusLedEnabled(ctx context.Context) (bool, error) {
+	var section ledSection
+	if err := c.getConfig(ctx, "getLedStatus", "led", "config", &section); err != nil {
+		return false, err
+	}
+	return section.Enabled == cameraOn, nil
+}
+
+func (c *Camera) SetStatusLed(ctx context.Context, enabled bool) error {
+	return c.setConfig(ctx, "setLedStatus", "led", "config", ledSection{Enabled: onOff(enabled)})
+}
+
+// GetVideoQuality returns the resolution and the bitrate in kbps of the main stream.
+func (c *Camera) GetVideoQuality(ctx context.Context) (string, int, error) {
+	var section videoSection
+	if err := c.getConfig(ctx, "getVideoQualities", "video", "main", &section); err != nil {
+		return "", 0, err
+	}
+	bitrate, err := strconv.Atoi(section.Bitrate)
+	if err != nil {
+		return "", 0, fmt.Errorf("unexpected bitrate: %q", section.Bitrate)
+	}
+	return section.Resolution, bitrate, nil
+}
+
+// SetVideoQuality sets the resolution, for example 1920*1080, and the bitrate in kbps of the main stream.
+func (c *Camera) SetVideoQuality(ctx context.Context, resolution string, bitrate int) error {
+	if resolution == "" || bitrate <= 0 {
+		return fmt.Errorf("invalid video quality: %q at %d kbps", resolution, bitrate)
+	}
+	section := videoSection{Resolution: resolution, Bitrate: strconv.Itoa(bitrate)}
+	return c.setConfig(ctx, "setVideoQualities", "video", "main", section)
+}
+
+// GetTimeZone returns the IANA zone id and the UTC offset the camera uses.
+func (c *Camera) GetTimeZone(ctx context.Context) (string, string, error) {
+	var section timezoneSection
+	if err := c.getConfig(ctx, "getTimezone", "system", "basic", &section); err != nil {
+		return "", "", err
+	}
+	return section.ZoneId, section.Timezone, nil
+}
+
+// SetTimeZone sets the IANA zone id, such as Europe/London, and its UTC offset, such as UTC+00:00.
+func (c *Camera) SetTimeZone(ctx context.Context, zoneId, timezone string) error {
+	if zoneId == "" || timezone == "" {
+		return fmt.Errorf("zone id and timezone must not be empty")
+	}
+	section := timezoneSection{TimingMode: "ntp", ZoneId: zoneId, Timezone: timezone}
+	return c.setConfig(ctx, "setTimezone", "system", "basic", section)
+}
+
+// GetSettings reads back every image and hardware setting of the camera.
+func (c *Camera) GetSettings(ctx context.Context) (*CameraSettings, error) {
+	var settings CameraSettings
+	var imageSwitch imageSwitchSection
+	if err := c.getConfig(ctx, "getLdc", "image", "switch", &imageSwitch); err != nil {
+		return nil, err
+	}
+	settings.NightVisionMode = NightVisionMode(imageSwitch.NightVisionMode)
+	settings.FlipImage = imageSwitch.FlipType == "center"
+	settings.LensDistortionCorrection = imageSwitch.Ldc == cameraOn
+
+	var err error
+	if settings.StatusLed, err = c.IsStatusLedEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if settings.Resolution, settings.Bitrate, err = c.GetVideoQuality(ctx); err != nil {
+		return nil, err
+	}
+	if settings.TimeZoneId, settings.TimeZone, err = c.GetTimeZone(ctx); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// DiffCameraSettings returns the names of the settings in which desired differs from current.
+// Empty string fields in desired are left as they are and never reported.
+func DiffCameraSettings(current, desired CameraSettings) []string {
+	diff := make([]string, 0)
+	if desired.NightVisionMode != "" && desired.NightVisionMode != current.NightVisionMode {
+		diff = append(diff, SettingNightVisionMode)
+	}
+	if desired.FlipImage != current.FlipImage {
+		diff = append(diff, SettingFlipImage)
+	}
+	if desired.LensDistortionCorrection != current.LensDistortionCorrection {
+		diff = append(diff, SettingLensDistortionCorrection)
+	}
+	if desired.StatusLed != current.StatusLed {
+		diff = append(diff, SettingStatusLed)
+	}
+	if desired.Resolution != "" && (desired.Resolution != current.Resolution || desired.Bitrate != current.Bitrate) {
+		diff = append(diff, SettingVideoQuality)
+	}
+	if desired.TimeZoneId != "" && (desired.TimeZoneId != current.TimeZoneId || desired.TimeZone != current.TimeZone) {
+		diff = append(diff, SettingTimeZone)
+	}
+	return diff
+}
+
+// ApplySettings reads the current settings, changes only those that differ from desired
+// and returns the names of the settings it changed.
+func (c *Camera) ApplySettings(ctx context.Context, desired CameraSettings) ([]string, error) {
+	current, err := c.GetSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	diff := DiffCameraSettings(*current, desired)
+	for i, setting := range diff {
+		switch setting {
+		case SettingNightVisionMode:
+			err = c.SetNightVisionMode(ctx, desired.NightVisionMode)
+		case SettingFlipImage:
+			err = c.SetImageFlip(ctx, desired.FlipImage)
+		case SettingLensDistortionCorrection:
+			err = c.SetLensDistortionCorrection(ctx, desired.LensDistortionCorrection)
+		case SettingStatusLed:
+			err = c.SetStatusLed(ctx, desired.StatusLed)
+		case SettingVideoQuality:
+			err = c.SetVideoQuality(ctx, desired.Resolution, desired.Bitrate)
+		case SettingTimeZone:
+			err = c.SetTimeZone(ctx, desired.TimeZoneId, desired.TimeZone)
+		}
+		if err != nil {
+			return diff[:i], fmt.Errorf("error applying %s: %w", setting, err)
+		}
+	}
+	return diff, nil
+}
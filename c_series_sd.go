@@ -0,0 +1,191 @@
+package tapo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// recordingDateLayout is the date format the camera uses for recording days.
+const recordingDateLayout = "20060102"
+
+// recordingsPageSize is the number of recording segments requested per page.
+const recordingsPageSize = 100
+
+// SdCardStatus is the state and capacity of the camera's SD card as reported by the camera,
+// for example "normal" or "unformatted", with sizes such as 59.6GB.
+type SdCardStatus struct {
+	Status            string `json:"status"`
+	TotalSpace        string `json:"total_space"`
+	FreeSpace         string `json:"free_space"`
+	VideoTotalSpace   string `json:"video_total_space"`
+	VideoFreeSpace    string `json:"video_free_space"`
+	Percent           string `json:"percent"`
+	RecordDuration    string `json:"record_duration"`
+	LoopRecordStatus  string `json:"loop_record_status"`
+	WriteProtect      string `json:"write_protect"`
+	DiskName          string `json:"disk_name"`
+	Type              string `json:"type"`
+	DetectStatus      string `json:"detect_status"`
+	RecordStartTime   string `json:"record_start_time"`
+	PictureTotalSpace string `json:"picture_total_space"`
+}
+
+// RecordingSegment is a single recording stored on the SD card.
+type RecordingSegment struct {
+	Start time.Time
+	End   time.Time
+	Type  int
+}
+
+type recordingSegmentResult struct {
+	StartTime int64 `json:"startTime"`
+	EndTime   int64 `json:"endTime"`
+	VideoType int   `json:"vedio_type"`
+}
+
+// GetSdCardStatus returns the status and capacity of the first SD card.
+func (c *Camera) GetSdCardStatus(ctx context.Context) (*SdCardStatus, error) {
+	var response struct {
+		HarddiskManage struct {
+			HdInfo []map[string]SdCardStatus `json:"hd_info"`
+		} `json:"harddisk_manage"`
+	}
+	params := map[string]map[string][]string{"harddisk_manage": {"table": {"hd_info"}}}
+	if err := c.executeMultipleRequest(ctx, "getSdCardStatus", params, &response); err != nil {
+		return nil, err
+	}
+	for _, disk := range response.HarddiskManage.HdInfo {
+		for _, status := range disk {
+			return &status, nil
+		}
+	}
+	return nil, fmt.Errorf("no SD card found")
+}
+
+// FormatSdCard erases the SD card. The camera keeps reporting the card as formatting for a while.
+func (c *Camera) FormatSdCard(ctx context.Context) error {
+	params := map[string]map[string]string{"harddisk_manage": {"format_hd": "1"}}
+	return c.executeMultipleRequest(ctx, "formatSdCard", params, nil)
+}
+
+// GetRecordingDays returns the days between start and end, inclusive, that have recordings.
+// The days are returned at midnight in the location of start.
+func (c *Camera) GetRecordingDays(ctx context.Context, start, end time.Time) ([]time.Time, error) {
+	var response struct {
+		Playback struct {
+			SearchResults []map[string]struct {
+				Date string `json:"date"`
+			} `json:"search_results"`
+		} `json:"playback"`
+	}
+	params := map[string]map[string]any{"playback": {"search_year_utility": map[string]any{
+		"channel":    []int{0},
+		"start_date": start.Format(recordingDateLayout),
+		"end_date":   end.In(start.Location()).Format(recordingDateLayout),
+	}}}
+	if err := c.executeMultipleRequest(ctx, "searchDateWithVideo", params, &response); err != nil {
+		return nil, err
+	}
+	days := make([]time.Time, 0, len(response.Playback.SearchResults))
+	for _, result := range response.Playback.SearchResults {
+		for _, r := range result {
+			day, err := time.ParseInLocation(recordingDateLayout, r.Date, start.Location())
+			if err != nil {
+				return nil, fmt.Errorf("unexpected recording date: %q", r.Date)
+			}
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days, nil
+}
+
+// GetUserId returns the id of the user the camera is connected as, which recording searches require.
+func (c *Camera) GetUserId(ctx context.Context) (int, error) {
+	var response struct {
+		UserId int `json:"user_id"`
+	}
+	params := map[string]map[string]string{"system": {"get_user_id": "null"}}
+	if err := c.executeMultipleRequest(ctx, "getUserID", params, &response); err != nil {
+		return 0, err
+	}
+	return response.UserId, nil
+}
+
+// GetRecordingsOfDay returns every recording segment of the given day, oldest first.
+func (c *Camera) GetRecordingsOfDay(ctx context.Context, day time.Time) ([]RecordingSegment, error) {
+	userId, err := c.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.getRecordingsOfDay(ctx, userId, day)
+}
+
+func (c *Camera) getRecordingsOfDay(ctx context.Context, userId int, day time.Time) ([]RecordingSegment, error) {
+	segments := make([]RecordingSegment, 0)
+	for startIndex := 0; ; startIndex += recordingsPageSize {
+		var response struct {
+			Playback struct {
+				SearchVideoResults []map[string]recordingSegmentResult `json:"search_video_results"`
+			} `json:"playback"`
+		}
+		params := map[string]map[string]any{"playback": {"search_video_utility": map[string]any{
+			"channel":     0,
+			"date":        day.Format(recordingDateLayout),
+			"start_index": startIndex,
+			"end_index":   startIndex + recordingsPageSize - 1,
+		}}}
+		if err := c.executeMultipleRequest(ctx, "searchVideoOfDay", params, &response); err != nil {
+			return nil, err
+		}
+		for _, result := range response.Playback.SearchVideoResults {
+			for _, r := range result {
+				segments = append(segments, RecordingSegment{
+					Start: time.Unix(r.StartTime, 0),
+					End:   time.Unix(r.EndTime, 0),
+					Type:  r.VideoType,
+				})
+			}
+		}
+		if len(response.Playback.SearchVideoResults) < recordingsPageSize {
+			break
+		}
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].Start.Before(segments[j].Start) })
+	return segments, nil
+}
+
+// SearchRecordings returns the recording segments that overlap the range from start to end, oldest first.
+func (c *Camera) SearchRecordings(ctx context.Context, start, end time.Time) ([]RecordingSegment, error) {
+	if !end.After(start) {
+		return nil, fmt.Errorf("end %s is not after start %s", end, start)
+	}
+	days, err := c.GetRecordingDays(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	userId, err := c.GetUserId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	segments := make([]RecordingSegment, 0)
+	for _, day := range days {
+		daySegments, err := c.getRecordingsOfDay(ctx, userId, day)
+		if err != nil {
+			return nil, err
+		}
+		for _, segment := range daySegments {
+			if segment.End.After(start) && segment.Start.Before(end) {
+				segments = append(segments, segment)
+			}
+		}
+	}
+	return segments, nil
+}
+
+// Duration returns the length of the recording.
+func (s RecordingSegment) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
@@ -0,0 +1,108 @@
+package tapo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CountdownRule switches the device into DesiredStates once Delay has passed.
+// Remaining is how long is left until then; it equals Delay for a rule that has not started.
+type CountdownRule struct {
+	Id            string
+	Enable        bool
+	Delay         time.Duration
+	Remaining     time.Duration
+	DesiredStates DesiredStates
+}
+
+type countdownRule struct {
+	Id            string        `json:"id,omitempty"`
+	Enable        bool          `json:"enable"`
+	Delay         int           `json:"delay"`
+	Remain        int           `json:"remain"`
+	DesiredStates DesiredStates `json:"desired_states"`
+}
+
+type countdownRulesPage struct {
+	Enable                bool            `json:"enable"`
+	RuleList              []countdownRule `json:"rule_list"`
+	CountdownRuleMaxCount int             `json:"countdown_rule_max_count"`
+	StartIndex            int             `json:"start_index"`
+	Sum                   int             `json:"sum"`
+}
+
+func (r countdownRule) typed() CountdownRule {
+	return CountdownRule{
+		Id:            r.Id,
+		Enable:        r.Enable,
+		Delay:         time.Duration(r.Delay) * time.Second,
+		Remaining:     time.Duration(r.Remain) * time.Second,
+		DesiredStates: r.DesiredStates,
+	}
+}
+
+func newCountdownRule(id string, delay time.Duration, on bool) (countdownRule, error) {
+	if delay < time.Second {
+		return countdownRule{}, fmt.Errorf("countdown delay %s is shorter than a second", delay)
+	}
+	seconds := int(delay / time.Second)
+	return countdownRule{
+		Id:            id,
+		Enable:        true,
+		Delay:         seconds,
+		Remain:        seconds,
+		DesiredStates: DesiredStates{On: on},
+	}, nil
+}
+
+// GetCountdownRules returns the countdown rules stored on the plug.
+func (t *SmartPlug) GetCountdownRules(ctx context.Context) ([]CountdownRule, error) {
+	rules := make([]CountdownRule, 0)
+	for {
+		var page countdownRulesPage
+		params := map[string]int{"start_index": len(rules)}
+		if err := t.executeSingleMethod(ctx, "get_countdown_rules", params, &page); err != nil {
+			return nil, err
+		}
+		for _, rule := range page.RuleList {
+			rules = append(rules, rule.typed())
+		}
+		if len(page.RuleList) == 0 || len(rules) >= page.Sum {
+			return rules, nil
+		}
+	}
+}
+
+// AddCountdownRule makes the plug switch on or off after delay and returns the rule id.
+// The countdown runs on the plug, so it completes even if the caller goes away.
+func (t *SmartPlug) AddCountdownRule(ctx context.Context, delay time.Duration, on bool) (string, error) {
+	rule, err := newCountdownRule("", delay, on)
+	if err != nil {
+		return "", err
+	}
+	var response struct {
+		Id string `json:"id"`
+	}
+	if err = t.executeSingleMethod(ctx, "add_countdown_rule", rule, &response); err != nil {
+		return "", err
+	}
+	return response.Id, nil
+}
+
+// EditCountdownRule restarts the countdown rule with the given id with a new delay and target state.
+func (t *SmartPlug) EditCountdownRule(ctx context.Context, id string, delay time.Duration, on bool) error {
+	if id == "" {
+		return fmt.Errorf("rule id must not be empty")
+	}
+	rule, err := newCountdownRule(id, delay, on)
+	if err != nil {
+		return err
+	}
+	return t.executeSingleMethod(ctx, "edit_countdown_rule", rule, nil)
+}
+
+// RemoveCountdownRules removes the countdown rules with the given ids, or all of them if no ids are given.
+func (t *SmartPlug) RemoveCountdownRules(ctx context.Context, ids ...string) error {
+	return t.removeRules(ctx, "remove_countdown_rules", ids)
+}
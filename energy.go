@@ -0,0 +1,367 @@
+package tapo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// EnergyInterval is the granularity of historical energy data, in minutes.
+type EnergyInterval int
+
+const (
+	EnergyIntervalHourly  EnergyInterval = 60
+	EnergyIntervalDaily   EnergyInterval = 1440
+	EnergyIntervalMonthly EnergyInterval = 43200
+)
+
+// Windows the device accepts for get_energy_data. Hourly data may cover at most 8 days, so it is
+// requested a week at a time. Daily data is returned for the quarter starting at start_timestamp
+// and monthly data for the year starting at it; for both, end_timestamp equals start_timestamp.
+const (
+	energyHourlyWindowDays = 7
+	monthsPerQuarter       = 3
+)
+
+// EnergyDataPoint is the energy used in the interval starting at Time.
+type EnergyDataPoint struct {
+	Time     time.Time
+	EnergyWh int
+}
+
+type EnergyDataResponse struct {
+	LocalTime      string `json:"local_time"`
+	Data           []int  `json:"data"`
+	StartTimestamp int64  `json:"start_timestamp"`
+	EndTimestamp   int64  `json:"end_timestamp"`
+	Interval       int    `json:"interval"`
+}
+
+// energyWindow is a range of a single get_energy_data request, in the device's local time.
+type energyWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// GetEnergyDataWindow returns the energy data of the single device window that contains start,
+// exactly as the device reports it. Timestamps in the response are in the device's local time,
+// see GetPowerData.
+func (t *SmartPlug) GetEnergyDataWindow(ctx context.Context, start time.Time, interval EnergyInterval) (*EnergyDataResponse, error) {
+	if _, err := energyStep(interval); err != nil {
+		return nil, err
+	}
+	timeDiff, err := t.deviceTimeDiff(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return t.getEnergyDataWindow(ctx, energyWindowOf(start.In(deviceLocation(timeDiff)), interval), interval)
+}
+
+func (t *SmartPlug) getEnergyDataWindow(ctx context.Context, window energyWindow, interval EnergyInterval) (*EnergyDataResponse, error) {
+	startTimestamp := deviceTimestamp(window.Start)
+	endTimestamp := startTimestamp
+	if interval == EnergyIntervalHourly {
+		endTimestamp = deviceTimestamp(window.End) - 1
+	}
+	params := map[string]int64{
+		"start_timestamp": startTimestamp,
+		"end_timestamp":   endTimestamp,
+		"interval":        int64(interval),
+	}
+	var response EnergyDataResponse
+	if err := t.executeSingleMethod(ctx, "get_energy_data", params, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetEnergyData returns the energy used between start and end at the given interval, oldest first.
+// The range is split into the windows the device accepts, aligned to days, quarters or years
+// in the device's local time, and the results are merged. Points are returned at their real time.
+func (t *SmartPlug) GetEnergyData(ctx context.Context, start, end time.Time, interval EnergyInterval) ([]EnergyDataPoint, error) {
+	if !end.After(start) {
+		return nil, fmt.Errorf("end %s is not after start %s", end, start)
+	}
+	if _, err := energyStep(interval); err != nil {
+		return nil, err
+	}
+	timeDiff, err := t.deviceTimeDiff(ctx)
+	if err != nil {
+		return nil, err
+	}
+	location := deviceLocation(timeDiff)
+	points := make([]EnergyDataPoint, 0)
+	for _, window := range energyWindows(start.In(location), end.In(location), interval) {
+		response, err := t.getEnergyDataWindow(ctx, window, interval)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, energyPoints(window, response.Data, interval, start, end)...)
+	}
+	return points, nil
+}
+
+// energyStep returns the function that advances a point by one interval.
+func energyStep(interval EnergyInterval) (func(time.Time) time.Time, error) {
+	switch interval {
+	case EnergyIntervalHourly:
+		return func(t time.Time) time.Time { return t.Add(time.Hour) }, nil
+	case EnergyIntervalDaily:
+		return func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }, nil
+	case EnergyIntervalMonthly:
+		return func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }, nil
+	}
+	return nil, fmt.Errorf("unsupported energy interval: %d", interval)
+}
+
+// energyWindows splits the range from start to end into the device windows that cover it.
+// The windows are aligned in the location of start.
+func energyWindows(start, end time.Time, interval EnergyInterval) []energyWindow {
+	var windows []energyWindow
+	for window := energyWindowOf(start, interval); window.Start.Before(end); window = energyWindowOf(window.End, interval) {
+		windows = append(windows, window)
+	}
+	return windows
+}
+
+// energyWindowOf returns the device window that contains t: a week starting at midnight of t's day
+// for hourly data, the quarter of t for daily data and the year of t for monthly data.
+func energyWindowOf(t time.Time, interval EnergyInterval) energyWindow {
+	year, month, day := t.Date()
+	switch interval {
+	case EnergyIntervalHourly:
+		start := time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+		return energyWindow{Start: start, End: start.AddDate(0, 0, energyHourlyWindowDays)}
+	case EnergyIntervalDaily:
+		quarterMonth := month - (month-time.January)%monthsPerQuarter
+		start := time.Date(year, quarterMonth, 1, 0, 0, 0, 0, t.Location())
+		return energyWindow{Start: start, End: start.AddDate(0, monthsPerQuarter, 0)}
+	default:
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, t.Location())
+		return energyWindow{Start: start, End: start.AddDate(1, 0, 0)}
+	}
+}
+
+// energyPoints places the values the device returned for a window at the start of their intervals,
+// counted from the window start, and keeps those from start to end. Values past the window,
+// which the device pads its answer with, are dropped.
+func energyPoints(window energyWindow, data []int, interval EnergyInterval, start, end time.Time) []EnergyDataPoint {
+	step, err := energyStep(interval)
+	if err != nil {
+		return nil
+	}
+	points := make([]EnergyDataPoint, 0, len(data))
+	pointTime := window.Start
+	for _, energy := range data {
+		if !pointTime.Before(window.End) {
+			break
+		}
+		if !pointTime.Before(start) && pointTime.Before(end) {
+			points = append(points, EnergyDataPoint{Time: pointTime, EnergyWh: energy})
+		}
+		pointTime = step(pointTime)
+	}
+	return points
+}
+
+// deviceTimeDiff returns how many seconds the device's local time is ahead of UTC.
+// The device reports and expects timestamps as its local wall clock time read as UTC.
+func (t *SmartPlug) deviceTimeDiff(ctx context.Context) (int64, error) {
+	info, err := t.DeviceInfo(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if info.ErrorCode != 0 {
+		return 0, fmt.Errorf("get_device_info failed with error code: %d", info.ErrorCode)
+	}
+	return int64(info.Result.TimeDiff) * 60, nil
+}
+
+// deviceLocation returns a location for the device's local time, timeDiff seconds ahead of UTC.
+func deviceLocation(timeDiff int64) *time.Location {
+	return time.FixedZone("device", int(timeDiff))
+}
+
+// deviceTimestamp returns the timestamp the device uses for t: its wall clock time in t's location read as UTC.
+func deviceTimestamp(t time.Time) int64 {
+	_, offset := t.Zone()
+	return t.Unix() + int64(offset)
+}
+
+// fromDeviceTimestamp is the inverse of deviceTimestamp for a device timeDiff seconds ahead of UTC.
+func fromDeviceTimestamp(timestamp, timeDiff int64) time.Time {
+	return time.Unix(timestamp-timeDiff, 0).In(deviceLocation(timeDiff))
+}
+
+// PowerInterval is the granularity of historical power samples, in minutes.
+type PowerInterval int
+
+const (
+	PowerIntervalFiveMinutes PowerInterval = 5
+	PowerIntervalHourly      PowerInterval = 60
+)
+
+// PowerSample is the average power drawn in the interval starting at Time.
+type PowerSample struct {
+	Time  time.Time
+	Watts int
+}
+
+type PowerDataResponse struct {
+	Data           []int `json:"data"`
+	StartTimestamp int64 `json:"start_timestamp"`
+	EndTimestamp   int64 `json:"end_timestamp"`
+	Interval       int   `json:"interval"`
+}
+
+// GetPowerData returns the power samples the device stored between start and end, oldest first.
+// The device keeps timestamps in its local time, which is TimeDiff minutes ahead of UTC;
+// the samples are returned at their real time, like the points of GetEnergyData.
+func (t *SmartPlug) GetPowerData(ctx context.Context, start, end time.Time, interval PowerInterval) ([]PowerSample, error) {
+	if interval != PowerIntervalFiveMinutes && interval != PowerIntervalHourly {
+		return nil, fmt.Errorf("unsupported power interval: %d", interval)
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("end %s is not after start %s", end, start)
+	}
+	timeDiff, err := t.deviceTimeDiff(ctx)
+	if err != nil {
+		return nil, err
+	}
+	location := deviceLocation(timeDiff)
+
+	params := map[string]int64{
+		"start_timestamp": deviceTimestamp(start.In(location)),
+		"end_timestamp":   deviceTimestamp(end.In(location)),
+		"interval":        int64(interval),
+	}
+	var response PowerDataResponse
+	if err = t.executeSingleMethod(ctx, "get_power_data", params, &response); err != nil {
+		return nil, err
+	}
+
+	step := time.Duration(interval) * time.Minute
+	first := fromDeviceTimestamp(response.StartTimestamp, timeDiff)
+	samples := make([]PowerSample, 0, len(response.Data))
+	for i, watts := range response.Data {
+		samples = append(samples, PowerSample{Time: first.Add(time.Duration(i) * step), Watts: watts})
+	}
+	return samples, nil
+}
+
+// TariffType is how the device prices electricity.
+type TariffType string
+
+const (
+	TariffConstant  TariffType = "constant"
+	TariffTimeOfUse TariffType = "time_of_use"
+)
+
+// Prices and charges are stored by the device in hundredths of the currency unit.
+const currencySubunits = 100
+
+// Number of hours a time-of-use day is split into.
+const hoursPerDay = 24
+
+// PeakLevel is the price level of an hour in a time-of-use tariff.
+type PeakLevel int
+
+const (
+	OffPeak PeakLevel = 0
+	MidPeak PeakLevel = 1
+	OnPeak  PeakLevel = 2
+)
+
+// TimeOfUseSeason holds the prices per kWh of a season, in hundredths of the currency unit.
+// Period is the start month, start day, end month and end day of the season.
+// WeekdayConfig and WeekendConfig give the PeakLevel of each hour of the day.
+type TimeOfUseSeason struct {
+	OnPeak        int         `json:"onpeak"`
+	MidPeak       int         `json:"midpeak"`
+	OffPeak       int         `json:"offpeak"`
+	Period        []int       `json:"period"`
+	WeekdayConfig []PeakLevel `json:"weekday_config"`
+	WeekendConfig []PeakLevel `json:"weekend_config"`
+}
+
+type TimeOfUseConfig struct {
+	Summer TimeOfUseSeason `json:"summer"`
+	Winter TimeOfUseSeason `json:"winter"`
+}
+
+// ElectricityPriceConfig is the tariff stored on the device. ConstantPrice is the price per kWh
+// in hundredths of the currency unit and only applies to a constant tariff.
+type ElectricityPriceConfig struct {
+	Type            TariffType       `json:"type"`
+	ConstantPrice   int              `json:"constant_price"`
+	TimeOfUseConfig *TimeOfUseConfig `json:"time_of_use_config,omitempty"`
+}
+
+// ElectricityCharges are the costs of the energy used, in the currency unit.
+type ElectricityCharges struct {
+	Past    float64
+	Current float64
+	Next    float64
+}
+
+// Charges decodes electricity_charge, which the device fills in from its tariff,
+// into the costs of the past, current and next periods.
+func (r *EnergyUsageResponse) Charges() ElectricityCharges {
+	var charges ElectricityCharges
+	for i, charge := range r.Result.ElectricityCharge {
+		amount := float64(charge) / currencySubunits
+		switch i {
+		case 0:
+			charges.Past = amount
+		case 1:
+			charges.Current = amount
+		case 2:
+			charges.Next = amount
+		}
+	}
+	return charges
+}
+
+// Validate checks the tariff against the limits the device accepts.
+func (c ElectricityPriceConfig) Validate() error {
+	switch c.Type {
+	case TariffConstant:
+		if c.ConstantPrice < 0 {
+			return fmt.Errorf("negative constant price: %d", c.ConstantPrice)
+		}
+	case TariffTimeOfUse:
+		if c.TimeOfUseConfig == nil {
+			return fmt.Errorf("time of use tariff needs a time of use config")
+		}
+		for name, season := range map[string]TimeOfUseSeason{"summer": c.TimeOfUseConfig.Summer, "winter": c.TimeOfUseConfig.Winter} {
+			if season.OnPeak < 0 || season.MidPeak < 0 || season.OffPeak < 0 {
+				return fmt.Errorf("%s: negative price", name)
+			}
+			if len(season.Period) != 4 {
+				return fmt.Errorf("%s: period must hold start month, start day, end month and end day", name)
+			}
+			if len(season.WeekdayConfig) != hoursPerDay || len(season.WeekendConfig) != hoursPerDay {
+				return fmt.Errorf("%s: weekday and weekend config must have %d hours", name, hoursPerDay)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported tariff type: %q", c.Type)
+	}
+	return nil
+}
+
+func (t *SmartPlug) GetElectricityPriceConfig(ctx context.Context) (*ElectricityPriceConfig, error) {
+	var response ElectricityPriceConfig
+	if err := t.executeSingleMethod(ctx, "get_electricity_price_config", nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SetElectricityPriceConfig stores a tariff on the plug, which it uses to compute electricity_charge.
+func (t *SmartPlug) SetElectricityPriceConfig(ctx context.Context, config ElectricityPriceConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	return t.executeSingleMethod(ctx, "set_electricity_price_config", config, nil)
+}
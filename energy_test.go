@@ -0,0 +1,185 @@
+package tapo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEnergyWindows(t *testing.T) {
+	device := deviceLocation(2 * 60 * 60)
+	date := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, device)
+	}
+	tests := []struct {
+		name       string
+		start, end time.Time
+		interval   EnergyInterval
+		want       []energyWindow
+	}{
+		{
+			name:     "hourly within a week",
+			start:    date(2024, time.March, 10, 5),
+			end:      date(2024, time.March, 12, 18),
+			interval: EnergyIntervalHourly,
+			want:     []energyWindow{{date(2024, time.March, 10, 0), date(2024, time.March, 17, 0)}},
+		},
+		{
+			name:     "hourly over two weeks",
+			start:    date(2024, time.March, 10, 5),
+			end:      date(2024, time.March, 24, 1),
+			interval: EnergyIntervalHourly,
+			want: []energyWindow{
+				{date(2024, time.March, 10, 0), date(2024, time.March, 17, 0)},
+				{date(2024, time.March, 17, 0), date(2024, time.March, 24, 0)},
+				{date(2024, time.March, 24, 0), date(2024, time.March, 31, 0)},
+			},
+		},
+		{
+			name:     "daily within a quarter",
+			start:    date(2024, time.May, 20, 0),
+			end:      date(2024, time.June, 2, 0),
+			interval: EnergyIntervalDaily,
+			want:     []energyWindow{{date(2024, time.April, 1, 0), date(2024, time.July, 1, 0)}},
+		},
+		{
+			name:     "daily across a year end",
+			start:    date(2023, time.December, 15, 0),
+			end:      date(2024, time.January, 15, 0),
+			interval: EnergyIntervalDaily,
+			want: []energyWindow{
+				{date(2023, time.October, 1, 0), date(2024, time.January, 1, 0)},
+				{date(2024, time.January, 1, 0), date(2024, time.April, 1, 0)},
+			},
+		},
+		{
+			name:     "end on a window boundary",
+			start:    date(2024, time.January, 1, 0),
+			end:      date(2024, time.April, 1, 0),
+			interval: EnergyIntervalDaily,
+			want:     []energyWindow{{date(2024, time.January, 1, 0), date(2024, time.April, 1, 0)}},
+		},
+		{
+			name:     "monthly over two years",
+			start:    date(2023, time.November, 1, 0),
+			end:      date(2024, time.February, 1, 0),
+			interval: EnergyIntervalMonthly,
+			want: []energyWindow{
+				{date(2023, time.January, 1, 0), date(2024, time.January, 1, 0)},
+				{date(2024, time.January, 1, 0), date(2025, time.January, 1, 0)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := energyWindows(tt.start, tt.end, tt.interval)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d windows %v, want %d %v", len(got), got, len(tt.want), tt.want)
+			}
+			for i := range got {
+				if !got[i].Start.Equal(tt.want[i].Start) || !got[i].End.Equal(tt.want[i].End) {
+					t.Errorf("window %d = %v-%v, want %v-%v", i, got[i].Start, got[i].End, tt.want[i].Start, tt.want[i].End)
+				}
+			}
+		})
+	}
+}
+
+func TestEnergyPoints(t *testing.T) {
+	device := deviceLocation(-5 * 60 * 60)
+	date := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, device)
+	}
+	tests := []struct {
+		name       string
+		window     energyWindow
+		data       []int
+		interval   EnergyInterval
+		start, end time.Time
+		want       []EnergyDataPoint
+	}{
+		{
+			name:     "hourly points clipped to the range",
+			window:   energyWindowOf(date(2024, time.March, 10, 0), EnergyIntervalHourly),
+			data:     []int{1, 2, 3, 4, 5},
+			interval: EnergyIntervalHourly,
+			start:    date(2024, time.March, 10, 1),
+			end:      date(2024, time.March, 10, 4),
+			want: []EnergyDataPoint{
+				{date(2024, time.March, 10, 1), 2},
+				{date(2024, time.March, 10, 2), 3},
+				{date(2024, time.March, 10, 3), 4},
+			},
+		},
+		{
+			name:     "daily points across month ends",
+			window:   energyWindowOf(date(2024, time.January, 30, 0), EnergyIntervalDaily),
+			data:     make([]int, 40),
+			interval: EnergyIntervalDaily,
+			start:    date(2024, time.January, 31, 0),
+			end:      date(2024, time.February, 2, 0),
+			want: []EnergyDataPoint{
+				{date(2024, time.January, 31, 0), 0},
+				{date(2024, time.February, 1, 0), 0},
+			},
+		},
+		{
+			name:     "monthly values past the window are dropped",
+			window:   energyWindowOf(date(2024, time.June, 1, 0), EnergyIntervalMonthly),
+			data:     []int{10, 20, 30, 40, 50, 60, 70, 80, 90, 100, 110, 120, 130},
+			interval: EnergyIntervalMonthly,
+			start:    date(2024, time.November, 1, 0),
+			end:      date(2025, time.March, 1, 0),
+			want: []EnergyDataPoint{
+				{date(2024, time.November, 1, 0), 110},
+				{date(2024, time.December, 1, 0), 120},
+			},
+		},
+		{
+			name:     "range given in another location",
+			window:   energyWindowOf(date(2024, time.March, 10, 0), EnergyIntervalHourly),
+			data:     []int{1, 2, 3},
+			interval: EnergyIntervalHourly,
+			start:    time.Date(2024, time.March, 10, 6, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, time.March, 10, 7, 0, 0, 0, time.UTC),
+			want:     []EnergyDataPoint{{date(2024, time.March, 10, 1), 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := energyPoints(tt.window, tt.data, tt.interval, tt.start, tt.end)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d points %v, want %d %v", len(got), got, len(tt.want), tt.want)
+			}
+			for i := range got {
+				if !got[i].Time.Equal(tt.want[i].Time) || got[i].EnergyWh != tt.want[i].EnergyWh {
+					t.Errorf("point %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDeviceTimestamp(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeDiff int64
+		wall     time.Time
+	}{
+		{name: "ahead of UTC", timeDiff: 60 * 60, wall: time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)},
+		{name: "behind UTC", timeDiff: -8 * 60 * 60, wall: time.Date(2024, time.July, 1, 12, 30, 0, 0, time.UTC)},
+		{name: "UTC", timeDiff: 0, wall: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The device reads its wall clock as UTC, so a device timestamp is the wall time's Unix time in UTC.
+			instant := tt.wall.Add(-time.Duration(tt.timeDiff) * time.Second)
+			timestamp := deviceTimestamp(instant.In(deviceLocation(tt.timeDiff)))
+			if timestamp != tt.wall.Unix() {
+				t.Errorf("deviceTimestamp = %d, want %d", timestamp, tt.wall.Unix())
+			}
+			if back := fromDeviceTimestamp(timestamp, tt.timeDiff); !back.Equal(instant) {
+				t.Errorf("fromDeviceTimestamp = %v, want %v", back, instant)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package tapo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FirmwareState is a step of a firmware update.
+type FirmwareState int
+
+const (
+	FirmwareIdle FirmwareState = iota
+	FirmwareDownloading
+	FirmwareFlashing
+	FirmwareRebooting
+	FirmwareDone
+	FirmwareFailed
+)
+
+func (s FirmwareState) String() string {
+	switch s {
+	case FirmwareIdle:
+		return "idle"
+	case FirmwareDownloading:
+		return "downloading"
+	case FirmwareFlashing:
+		return "flashing"
+	case FirmwareRebooting:
+		return "rebooting"
+	case FirmwareDone:
+		return "done"
+	case FirmwareFailed:
+		return "failed"
+	}
+	return fmt.Sprintf("unknown (%d)", int(s))
+}
+
+// Values of status in get_fw_download_state. Negative values are errors.
+const (
+	fwStatusIdle        = 0
+	fwStatusRequested   = 1
+	fwStatusDownloading = 2
+	fwStatusFlashing    = 3
+)
+
+// ErrFirmwareUpToDate is returned by Update when the device already runs the latest firmware.
+var ErrFirmwareUpToDate = errors.New("firmware is up to date")
+
+// FirmwareInfo describes the latest firmware available for a device.
+type FirmwareInfo struct {
+	FwVer         string `json:"fw_ver"`
+	ReleaseDate   string `json:"release_date"`
+	ReleaseNote   string `json:"release_note"`
+	FwSize        int    `json:"fw_size"`
+	Type          int    `json:"type"`
+	NeedToUpgrade bool   `json:"need_to_upgrade"`
+}
+
+// FirmwareUpdateState is the progress of a firmware update. Progress is the download progress in percent,
+// Status the raw status reported by the device and RebootTime how many seconds the device needs to reboot.
+type FirmwareUpdateState struct {
+	State      FirmwareState
+	Progress   int
+	Status     int
+	RebootTime int
+}
+
+type firmwareDownloadState struct {
+	Status           int  `json:"status"`
+	DownloadProgress int  `json:"download_progress"`
+	RebootTime       int  `json:"reboot_time"`
+	UpgradeTime      int  `json:"upgrade_time"`
+	AutoUpgrade      bool `json:"auto_upgrade"`
+}
+
+// FirmwareUpdater checks for, installs and follows firmware updates of a single device.
+type FirmwareUpdater struct {
+	call      func(ctx context.Context, method string, params any, result any) error
+	version   func(ctx context.Context) (string, error)
+	reconnect func(ctx context.Context) error
+}
+
+// Firmware returns the firmware updater of the plug.
+func (t *SmartPlug) Firmware() *FirmwareUpdater {
+	return t.firmwareUpdater()
+}
+
+// Firmware returns the firmware updater of the light.
+func (b *SmartBulb) Firmware() *FirmwareUpdater {
+	return b.firmwareUpdater()
+}
+
+// ChildFirmware returns the firmware updater of the child device with the given id.
+// The update goes through the hub, which stays reachable while the child reboots.
+func (h *Hub) ChildFirmware(deviceId string) *FirmwareUpdater {
+	return &FirmwareUpdater{
+		call: func(ctx context.Context, method string, params any, result any) error {
+			return h.ExecuteChildMethod(ctx, deviceId, method, params, result)
+		},
+		version: func(ctx context.Context) (string, error) {
+			var info HubChildInfo
+			if err := h.ExecuteChildMethod(ctx, deviceId, "get_device_info", nil, &info); err != nil {
+				return "", err
+			}
+			return info.FwVer, nil
+		},
+	}
+}
+
+func (d *Device) firmwareUpdater() *FirmwareUpdater {
+	return &FirmwareUpdater{
+		call: d.executeSingleMethod,
+		version: func(ctx context.Context) (string, error) {
+			var info DeviceInfoResponse
+			if err := d.ExecuteMethod(ctx, "get_device_info", nil, &info); err != nil {
+				return "", err
+			}
+			if info.ErrorCode != 0 {
+				return "", &DeviceError{Method: "get_device_info", Code: info.ErrorCode}
+			}
+			return info.Result.FwVer, nil
+		},
+		reconnect: d.reconnect,
+	}
+}
+
+func (f *FirmwareUpdater) GetLatestFirmware(ctx context.Context) (*FirmwareInfo, error) {
+	var response FirmwareInfo
+	if err := f.call(ctx, "get_latest_firmware", nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// StartUpdate makes the device download and install the latest firmware.
+func (f *FirmwareUpdater) StartUpdate(ctx context.Context) error {
+	return f.call(ctx, "fw_download", nil, nil)
+}
+
+// GetUpdateState returns the download and installation progress reported by the device.
+// It never reports FirmwareRebooting or FirmwareDone, which only WaitForVersion can observe.
+func (f *FirmwareUpdater) GetUpdateState(ctx context.Context) (*FirmwareUpdateState, error) {
+	var response firmwareDownloadState
+	if err := f.call(ctx, "get_fw_download_state", nil, &response); err != nil {
+		return nil, err
+	}
+	state := &FirmwareUpdateState{
+		Progress:   response.DownloadProgress,
+		Status:     response.Status,
+		RebootTime: response.RebootTime,
+	}
+	switch {
+	case response.Status < 0:
+		state.State = FirmwareFailed
+	case response.Status == fwStatusRequested || response.Status == fwStatusDownloading:
+		state.State = FirmwareDownloading
+	case response.Status == fwStatusFlashing:
+		state.State = FirmwareFlashing
+	default:
+		state.State = FirmwareIdle
+	}
+	return state, nil
+}
+
+// Update installs the latest firmware and waits until the device is back with it, reporting every
+// state change to onState, which may be nil. It returns ErrFirmwareUpToDate if there is nothing to install.
+func (f *FirmwareUpdater) Update(ctx context.Context, pollInterval time.Duration, onState func(FirmwareUpdateState)) (*FirmwareInfo, error) {
+	if pollInterval <= 0 {
+		return nil, fmt.Errorf("poll interval must be positive: %s", pollInterval)
+	}
+	latest, err := f.GetLatestFirmware(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !latest.NeedToUpgrade {
+		return latest, ErrFirmwareUpToDate
+	}
+	if err = f.StartUpdate(ctx); err != nil {
+		return latest, err
+	}
+	return latest, f.WaitForVersion(ctx, latest.FwVer, pollInterval, onState)
+}
+
+// WaitForVersion follows an update started with StartUpdate every pollInterval until the device
+// reports fwVer, reporting every state change to onState, which may be nil.
+// Once the device stops answering, or for a hub child the hub can no longer reach it, the device is
+// considered rebooting and its version is polled, reconnecting if needed, until ctx expires.
+// An error code returned by the device itself ends the wait right away.
+func (f *FirmwareUpdater) WaitForVersion(ctx context.Context, fwVer string, pollInterval time.Duration, onState func(FirmwareUpdateState)) error {
+	if pollInterval <= 0 {
+		return fmt.Errorf("poll interval must be positive: %s", pollInterval)
+	}
+	current := FirmwareUpdateState{State: FirmwareIdle}
+	report := func(state FirmwareUpdateState) {
+		if state != current && onState != nil {
+			onState(state)
+		}
+		current = state
+	}
+
+	started := false
+	var lastErr error
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("waiting for firmware %s: %w, last error: %w", fwVer, ctx.Err(), lastErr)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		if current.State != FirmwareRebooting {
+			state, err := f.GetUpdateState(ctx)
+			switch {
+			case err != nil && !isRebootError(err):
+				return err
+			case err != nil:
+				// The device may already be rebooting at the first poll after a fast download.
+				lastErr = err
+				report(FirmwareUpdateState{State: FirmwareRebooting, Progress: 100})
+			case state.State == FirmwareFailed:
+				report(*state)
+				return fmt.Errorf("firmware update failed with status: %d", state.Status)
+			case state.State == FirmwareDownloading || state.State == FirmwareFlashing:
+				started = true
+				report(*state)
+				continue
+			case started:
+				report(FirmwareUpdateState{State: FirmwareRebooting, Progress: 100, RebootTime: state.RebootTime})
+			}
+		}
+
+		version, err := f.version(ctx)
+		if err != nil {
+			if !isRebootError(err) {
+				return err
+			}
+			lastErr = err
+			if f.reconnect != nil && current.State == FirmwareRebooting {
+				// The device drops its session when it reboots; a failed reconnect is retried on the next poll.
+				if err = f.reconnect(ctx); err != nil {
+					lastErr = err
+				}
+			}
+			continue
+		}
+		if version == fwVer {
+			report(FirmwareUpdateState{State: FirmwareDone, Progress: 100})
+			return nil
+		}
+	}
+}
+
+// isRebootError reports whether err means the device could not be reached, as happens while it reboots,
+// rather than the device answering with an error code.
+func isRebootError(err error) bool {
+	var deviceErr *DeviceError
+	return errors.Is(err, ErrChildUnreachable) || !errors.As(err, &deviceErr)
+}
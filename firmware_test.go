@@ -0,0 +1,106 @@
+package tapo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWaitForVersion(t *testing.T) {
+	errTimeout := errors.New("i/o timeout")
+	downloading := firmwareDownloadState{Status: fwStatusDownloading, DownloadProgress: 50}
+	idle := firmwareDownloadState{Status: fwStatusIdle}
+	tests := []struct {
+		name        string
+		states      []any // firmwareDownloadState or error, one per get_fw_download_state call
+		versions    []any // string or error, one per version call
+		wantErr     string
+		wantTimeout bool
+	}{
+		{
+			name:     "download then reboot",
+			states:   []any{downloading, idle},
+			versions: []any{"1.0", "2.0"},
+		},
+		{
+			name:     "unreachable at the first poll",
+			states:   []any{errTimeout},
+			versions: []any{errTimeout, "2.0"},
+		},
+		{
+			name:     "hub cannot reach the child",
+			states:   []any{childControlError("child", &DeviceError{Method: "control_child", Code: -1})},
+			versions: []any{"2.0"},
+		},
+		{
+			name:    "device error code",
+			states:  []any{&DeviceError{Method: "get_fw_download_state", Code: -40210}},
+			wantErr: "error code: -40210",
+		},
+		{
+			name:     "device error code while reading the version",
+			states:   []any{errTimeout},
+			versions: []any{&DeviceError{Method: "get_device_info", Code: -1501}},
+			wantErr:  "error code: -1501",
+		},
+		{
+			name:    "update failed",
+			states:  []any{firmwareDownloadState{Status: -1}},
+			wantErr: "update failed",
+		},
+		{
+			name:        "never comes back",
+			states:      []any{errTimeout},
+			versions:    []any{errTimeout},
+			wantTimeout: true,
+			wantErr:     "i/o timeout",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := func(values *[]any) any {
+				value := (*values)[0]
+				if len(*values) > 1 {
+					*values = (*values)[1:]
+				}
+				return value
+			}
+			states, versions := tt.states, tt.versions
+			f := &FirmwareUpdater{
+				call: func(ctx context.Context, method string, params any, result any) error {
+					value := next(&states)
+					if err, ok := value.(error); ok {
+						return err
+					}
+					body, _ := json.Marshal(value)
+					return json.Unmarshal(body, result)
+				},
+				version: func(ctx context.Context) (string, error) {
+					value := next(&versions)
+					if err, ok := value.(error); ok {
+						return "", err
+					}
+					return value.(string), nil
+				},
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			err := f.WaitForVersion(ctx, "2.0", time.Millisecond, nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got error %v, want one containing %q", err, tt.wantErr)
+			}
+			if timedOut := errors.Is(err, context.DeadlineExceeded); timedOut != tt.wantTimeout {
+				t.Errorf("timed out = %t, want %t", timedOut, tt.wantTimeout)
+			}
+		})
+	}
+}
@@ -3,29 +3,127 @@ package tapo
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// ErrUnsupportedMethod is returned for hub methods that the hub's protocol does not offer.
+var ErrUnsupportedMethod = errors.New("method not supported by this hub")
+
+// ErrChildUnreachable is returned by ExecuteChildMethod when the hub fails to pass a method
+// to the child, for example because the child is offline or rebooting.
+var ErrChildUnreachable = errors.New("child device unreachable")
+
+// HubProtocol is the method set a hub speaks.
+type HubProtocol int
+
+const (
+	// HubProtocolCamelCase is used by the H200: camelCase methods wrapped in a multipleRequest.
+	HubProtocolCamelCase HubProtocol = iota
+	// HubProtocolSnakeCase is used by the H100: snake_case methods sent directly, like the plugs.
+	HubProtocolSnakeCase
 )
 
 type Hub struct {
 	*Device
+	snakeCase bool
 }
 
+// NewHub connects to an H200 hub, which uses the SSL AES transport.
 func NewHub(ctx context.Context, host, email, password string, options Options) (*Hub, error) {
 	tr, err := NewSslAesTransport(ctx, host, email, password, options)
 	if err != nil {
 		return nil, err
 	}
-	tapo := NewDevice(tr, options)
-	return &Hub{tapo}, nil
+	return NewHubWithTransport(tr, HubProtocolCamelCase, options), nil
 }
 
+// NewKlapHub connects to an H100 hub, which uses the KLAP transport like the smart plugs.
+func NewKlapHub(ctx context.Context, host, email, password string, options Options) (*Hub, error) {
+	tr, err := NewKlapTransport(ctx, email, password, host, options)
+	if err != nil {
+		return nil, err
+	}
+	return NewHubWithTransport(tr, HubProtocolSnakeCase, options), nil
+}
+
+// NewHubWithTransport builds a hub that speaks the given protocol on an existing transport.
+func NewHubWithTransport(transport Transport, protocol HubProtocol, options Options) *Hub {
+	return &Hub{Device: NewDevice(transport, options), snakeCase: protocol == HubProtocolSnakeCase}
+}
+
+// GetChildDevices returns every child device of the hub. The hub reports children in pages,
+// which are requested until the reported sum is reached; the response holds one entry per page.
 func (h *Hub) GetChildDevices(ctx context.Context) (ChildDeviceListResponse, error) {
-	params := json.RawMessage("{\"requests\":[{\"method\":\"getChildDeviceList\",\"params\":{\"childControl\":{\"start_index\":0}}}]}")
 	var response ChildDeviceListResponse
-	err := h.ExecuteMethod(ctx, "multipleRequest", params, &response)
+	startIndex := 0
+	for {
+		page, err := h.GetChildDevicesPage(ctx, startIndex)
+		if err != nil {
+			return response, err
+		}
+		response.ErrorCode = page.ErrorCode
+		if page.ErrorCode != 0 || len(page.Result.Responses) == 0 {
+			return response, nil
+		}
+		response.Result.Responses = append(response.Result.Responses, page.Result.Responses...)
+		result := page.Result.Responses[0].Result
+		var entries []json.RawMessage
+		if len(result.ChildDeviceList) > 0 {
+			if err = json.Unmarshal(result.ChildDeviceList, &entries); err != nil {
+				return response, err
+			}
+		}
+		startIndex += len(entries)
+		if len(entries) == 0 || startIndex >= result.Sum {
+			return response, nil
+		}
+	}
+}
+
+// GetChildDevicesPage returns a single page of child devices starting at startIndex.
+func (h *Hub) GetChildDevicesPage(ctx context.Context, startIndex int) (ChildDeviceListResponse, error) {
+	if h.snakeCase {
+		return h.getKlapChildDevicesPage(ctx, startIndex)
+	}
+	params, err := json.Marshal(hubMultipleRequest{Requests: []hubRequest{{
+		Method: "getChildDeviceList",
+		Params: childControlParams{ChildControl: map[string]int{"start_index": startIndex}},
+	}}})
+	if err != nil {
+		return ChildDeviceListResponse{}, err
+	}
+	var response ChildDeviceListResponse
+	err = h.ExecuteMethod(ctx, "multipleRequest", params, &response)
 	return response, err
 }
 
+// GetChildDeviceList returns every child device of the hub decoded into its concrete type:
+// *MotionSensorInfo, *LeakSensorInfo, *TSeriesResponse, *RadiatorValveInfo, *RemoteInfo or *HubSwitchInfo.
+// Children of models the library does not know are returned as *UnknownChildDevice.
+func (h *Hub) GetChildDeviceList(ctx context.Context) ([]ChildDevice, error) {
+	entries, err := h.childDeviceEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	devices := make([]ChildDevice, 0, len(entries))
+	for _, entry := range entries {
+		device, err := DecodeChildDevice(entry)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
 func (h *Hub) GetDeviceInfo(ctx context.Context) (HubDeviceInfoResponse, error) {
+	if h.snakeCase {
+		return h.getKlapDeviceInfo(ctx)
+	}
 	params := json.RawMessage("{\"requests\":[{\"method\":\"getDeviceInfo\",\"params\":{\"device_info\": {\"name\": [\"basic_info\"]}}}]}")
 	var response HubDeviceInfoResponse
 	err := h.ExecuteMethod(ctx, "multipleRequest", params, &response)
@@ -34,77 +132,543 @@ func (h *Hub) GetDeviceInfo(ctx context.Context) (HubDeviceInfoResponse, error)
 
 type HubDeviceInfoResponse struct {
 	Result struct {
-		Responses []struct {
-			Method string `json:"method"`
-			Result struct {
-				DeviceInfo struct {
-					BasicInfo struct {
-						DeviceType           string `json:"device_type"`
-						DeviceModel          string `json:"device_model"`
-						DeviceName           string `json:"device_name"`
-						DeviceInfo           string `json:"device_info"`
-						HwVersion            string `json:"hw_version"`
-						SwVersion            string `json:"sw_version"`
-						DeviceAlias          string `json:"device_alias"`
-						Mac                  string `json:"mac"`
-						DevId                string `json:"dev_id"`
-						OemId                string `json:"oem_id"`
-						HwId                 string `json:"hw_id"`
-						Status               string `json:"status"`
-						BindStatus           bool   `json:"bind_status"`
-						ChildNum             int    `json:"child_num"`
-						Avatar               string `json:"avatar"`
-						Latitude             int    `json:"latitude"`
-						Longitude            int    `json:"longitude"`
-						HasSetLocationInfo   int    `json:"has_set_location_info"`
-						NeedSyncSha1Password int    `json:"need_sync_sha1_password"`
-						ProductName          string `json:"product_name"`
-						Region               string `json:"region"`
-						LocalIp              string `json:"local_ip"`
-					} `json:"basic_info"`
-					Info struct {
-						DeviceType           string `json:"device_type"`
-						DeviceModel          string `json:"device_model"`
-						DeviceName           string `json:"device_name"`
-						DeviceInfo           string `json:"device_info"`
-						HwVersion            string `json:"hw_version"`
-						SwVersion            string `json:"sw_version"`
-						DeviceAlias          string `json:"device_alias"`
-						Mac                  string `json:"mac"`
-						DevId                string `json:"dev_id"`
-						OemId                string `json:"oem_id"`
-						HwId                 string `json:"hw_id"`
-						Status               string `json:"status"`
-						BindStatus           bool   `json:"bind_status"`
-						ChildNum             int    `json:"child_num"`
-						Avatar               string `json:"avatar"`
-						Latitude             int    `json:"latitude"`
-						Longitude            int    `json:"longitude"`
-						HasSetLocationInfo   int    `json:"has_set_location_info"`
-						NeedSyncSha1Password int    `json:"need_sync_sha1_password"`
-						ProductName          string `json:"product_name"`
-						Region               string `json:"region"`
-						LocalIp              string `json:"local_ip"`
-					} `json:"info"`
-				} `json:"device_info"`
-			} `json:"result"`
-			ErrorCode int `json:"error_code"`
-		} `json:"responses"`
+		Responses []HubDeviceInfoMethodResponse `json:"responses"`
+	} `json:"result"`
+	ErrorCode int `json:"error_code"`
+}
+
+type HubDeviceInfoMethodResponse struct {
+	Method string `json:"method"`
+	Result struct {
+		DeviceInfo HubDeviceInfo `json:"device_info"`
 	} `json:"result"`
 	ErrorCode int `json:"error_code"`
 }
 
+type HubDeviceInfo struct {
+	BasicInfo HubBasicInfo `json:"basic_info"`
+	Info      HubBasicInfo `json:"info"`
+}
+
+type HubBasicInfo struct {
+	DeviceType           string `json:"device_type"`
+	DeviceModel          string `json:"device_model"`
+	DeviceName           string `json:"device_name"`
+	DeviceInfo           string `json:"device_info"`
+	HwVersion            string `json:"hw_version"`
+	SwVersion            string `json:"sw_version"`
+	DeviceAlias          string `json:"device_alias"`
+	Mac                  string `json:"mac"`
+	DevId                string `json:"dev_id"`
+	OemId                string `json:"oem_id"`
+	HwId                 string `json:"hw_id"`
+	Status               string `json:"status"`
+	BindStatus           bool   `json:"bind_status"`
+	ChildNum             int    `json:"child_num"`
+	Avatar               string `json:"avatar"`
+	Latitude             int    `json:"latitude"`
+	Longitude            int    `json:"longitude"`
+	HasSetLocationInfo   int    `json:"has_set_location_info"`
+	NeedSyncSha1Password int    `json:"need_sync_sha1_password"`
+	ProductName          string `json:"product_name"`
+	Region               string `json:"region"`
+	LocalIp              string `json:"local_ip"`
+}
+
 type ChildDeviceListResponse struct {
 	Result struct {
-		Responses []struct {
-			Method string `json:"method"`
-			Result struct {
-				StartIndex      int             `json:"start_index"`
-				ChildDeviceList json.RawMessage `json:"child_device_list"`
-				Sum             int             `json:"sum"`
-			} `json:"result"`
-			ErrorCode int `json:"error_code"`
-		} `json:"responses"`
+		Responses []ChildDeviceListMethodResponse `json:"responses"`
+	} `json:"result"`
+	ErrorCode int `json:"error_code"`
+}
+
+type ChildDeviceListMethodResponse struct {
+	Method    string              `json:"method"`
+	Result    ChildDeviceListPage `json:"result"`
+	ErrorCode int                 `json:"error_code"`
+}
+
+// ChildDeviceListPage is a page of child devices. ChildDeviceList holds the raw entries, see DecodeChildDevice.
+type ChildDeviceListPage struct {
+	StartIndex      int             `json:"start_index"`
+	ChildDeviceList json.RawMessage `json:"child_device_list"`
+	Sum             int             `json:"sum"`
+}
+
+type hubRequest struct {
+	Method string `json:"method"`
+	Params any    `json:"params,omitempty"`
+}
+
+type hubMultipleRequest struct {
+	Requests []hubRequest `json:"requests"`
+}
+
+type hubResponse struct {
+	Method    string          `json:"method"`
+	Result    json.RawMessage `json:"result"`
+	ErrorCode int             `json:"error_code"`
+}
+
+type hubMultipleResponse struct {
+	Result struct {
+		Responses []hubResponse `json:"responses"`
 	} `json:"result"`
 	ErrorCode int `json:"error_code"`
 }
+
+type childControlResult struct {
+	ResponseData hubMultipleResponse `json:"response_data"`
+}
+
+// ExecuteChildMethod sends a single method to the child device with the given id through the hub
+// and decodes the child's result into result. result may be nil if the response is not needed.
+func (h *Hub) ExecuteChildMethod(ctx context.Context, deviceId string, method string, params any, result any) error {
+	requestData := hubRequest{
+		Method: "multipleRequest",
+		Params: hubMultipleRequest{Requests: []hubRequest{{Method: method, Params: params}}},
+	}
+	var control childControlResult
+	if h.snakeCase {
+		if err := h.executeKlapChildMethod(ctx, deviceId, requestData, &control); err != nil {
+			return childControlError(deviceId, err)
+		}
+	} else {
+		childParams := childControlParams{ChildControl: map[string]any{
+			"device_id":    deviceId,
+			"request_data": requestData,
+		}}
+		if err := h.executeHubMethod(ctx, "controlChild", childParams, &control); err != nil {
+			return childControlError(deviceId, err)
+		}
+	}
+	if control.ResponseData.ErrorCode != 0 {
+		return fmt.Errorf("child %s: %w", deviceId, &DeviceError{Method: method, Code: control.ResponseData.ErrorCode})
+	}
+	responses := control.ResponseData.Result.Responses
+	if len(responses) == 0 {
+		return fmt.Errorf("child %s: empty response for %s", deviceId, method)
+	}
+	if responses[0].ErrorCode != 0 {
+		return fmt.Errorf("child %s: %w", deviceId, &DeviceError{Method: method, Code: responses[0].ErrorCode})
+	}
+	if result == nil || len(responses[0].Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(responses[0].Result, result)
+}
+
+// childControlError marks an error code the hub returned for the child control method itself,
+// rather than one returned by the child, with ErrChildUnreachable.
+func childControlError(deviceId string, err error) error {
+	var deviceErr *DeviceError
+	if errors.As(err, &deviceErr) {
+		return fmt.Errorf("child %s: %w: %w", deviceId, ErrChildUnreachable, err)
+	}
+	return err
+}
+
+// executeHubMethod sends a single camelCase method to the hub wrapped in a multipleRequest
+// and decodes the method's result into result. Hubs speaking HubProtocolSnakeCase do not offer these methods.
+func (h *Hub) executeHubMethod(ctx context.Context, method string, params any, result any) error {
+	if h.snakeCase {
+		return fmt.Errorf("%w: %s", ErrUnsupportedMethod, method)
+	}
+	return h.executeMultipleRequest(ctx, method, params, result)
+}
+
+// TriggerLog is a single event recorded by a hub child device, such as a motion or water leak event.
+type TriggerLog struct {
+	Id        int             `json:"id"`
+	EventId   string          `json:"eventId"`
+	Event     string          `json:"event"`
+	Timestamp int64           `json:"timestamp"`
+	Params    json.RawMessage `json:"params,omitempty"`
+}
+
+// Time returns the moment the event was recorded.
+func (l TriggerLog) Time() time.Time {
+	return time.Unix(l.Timestamp, 0)
+}
+
+type TriggerLogsResponse struct {
+	StartId int          `json:"start_id"`
+	Logs    []TriggerLog `json:"logs"`
+	Sum     int          `json:"sum"`
+}
+
+// GetTriggerLogs returns up to pageSize trigger logs of the child device, newest first, starting from startId.
+// A startId of 0 starts from the most recent event.
+func (h *Hub) GetTriggerLogs(ctx context.Context, deviceId string, pageSize, startId int) (*TriggerLogsResponse, error) {
+	params := map[string]int{"page_size": pageSize, "start_id": startId}
+	var response TriggerLogsResponse
+	err := h.ExecuteChildMethod(ctx, deviceId, "get_trigger_logs", params, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// childDeviceEntries returns the raw child device entries reported by the hub.
+func (h *Hub) childDeviceEntries(ctx context.Context) ([]json.RawMessage, error) {
+	devices, err := h.GetChildDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if devices.ErrorCode != 0 {
+		return nil, fmt.Errorf("error getting devices, error code: %d", devices.ErrorCode)
+	}
+	var entries []json.RawMessage
+	for _, d := range devices.Result.Responses {
+		if len(d.Result.ChildDeviceList) == 0 {
+			continue
+		}
+		var page []json.RawMessage
+		if err = json.Unmarshal(d.Result.ChildDeviceList, &page); err != nil {
+			return nil, err
+		}
+		entries = append(entries, page...)
+	}
+	return entries, nil
+}
+
+// Valid siren volume and duration ranges.
+const (
+	sirenMinVolume   = 1
+	sirenMaxVolume   = 10
+	sirenMinDuration = 1
+	sirenMaxDuration = 6000
+)
+
+// SirenConfig is the alarm configuration of the hub siren. Duration is in seconds.
+// The H200 uses Volume, 1-10, and the H100 uses AlarmVolume; the other field is left zero.
+type SirenConfig struct {
+	SirenType   string
+	Volume      int
+	AlarmVolume AlarmVolume
+	Duration    int
+}
+
+type SirenStatus struct {
+	Status   string `json:"status"`
+	TimeLeft int    `json:"time_left"`
+}
+
+// IsOn reports whether the siren is currently sounding.
+func (s SirenStatus) IsOn() bool {
+	return s.Status == "on"
+}
+
+type sirenConfigParams struct {
+	SirenType string `json:"siren_type,omitempty"`
+	Volume    string `json:"volume,omitempty"`
+	Duration  int    `json:"duration,omitempty"`
+}
+
+type sirenParams struct {
+	Siren any `json:"siren"`
+}
+
+// GetAlarmTones returns the names of the tones the hub siren can play.
+func (h *Hub) GetAlarmTones(ctx context.Context) ([]string, error) {
+	if h.snakeCase {
+		return h.getKlapAlarmTones(ctx)
+	}
+	var response struct {
+		SirenTypeList []string `json:"siren_type_list"`
+	}
+	err := h.executeHubMethod(ctx, "getSirenTypeList", sirenParams{Siren: struct{}{}}, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.SirenTypeList, nil
+}
+
+func (h *Hub) GetAlarmConfig(ctx context.Context) (*SirenConfig, error) {
+	if h.snakeCase {
+		return h.getKlapAlarmConfig(ctx)
+	}
+	var response sirenConfigParams
+	err := h.executeHubMethod(ctx, "getSirenConfig", sirenParams{Siren: struct{}{}}, &response)
+	if err != nil {
+		return nil, err
+	}
+	volume, err := strconv.Atoi(response.Volume)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected siren volume: %q", response.Volume)
+	}
+	return &SirenConfig{SirenType: response.SirenType, Volume: volume, Duration: response.Duration}, nil
+}
+
+// SetAlarmConfig sets the tone, volume and duration in seconds of the hub siren.
+// The tone must be one of the names returned by GetAlarmTones.
+func (h *Hub) SetAlarmConfig(ctx context.Context, config SirenConfig) error {
+	if h.snakeCase {
+		if config.Volume != 0 {
+			return errors.New("this hub takes the siren volume as AlarmVolume, not Volume")
+		}
+		if _, ok := klapAlarmVolumes[config.AlarmVolume]; !ok {
+			return fmt.Errorf("siren alarm volume %d is outside %d-%d", config.AlarmVolume, AlarmVolumeLow, AlarmVolumeHigh)
+		}
+	} else {
+		if config.AlarmVolume != 0 {
+			return errors.New("this hub takes the siren volume as Volume, not AlarmVolume")
+		}
+		if config.Volume < sirenMinVolume || config.Volume > sirenMaxVolume {
+			return fmt.Errorf("siren volume %d is outside %d-%d", config.Volume, sirenMinVolume, sirenMaxVolume)
+		}
+	}
+	if config.Duration < sirenMinDuration || config.Duration > sirenMaxDuration {
+		return fmt.Errorf("siren duration %d is outside %d-%d", config.Duration, sirenMinDuration, sirenMaxDuration)
+	}
+	tones, err := h.GetAlarmTones(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(tones, config.SirenType) {
+		return fmt.Errorf("unsupported siren type: %q", config.SirenType)
+	}
+	if h.snakeCase {
+		return h.setKlapAlarmConfig(ctx, config)
+	}
+	params := sirenParams{Siren: sirenConfigParams{
+		SirenType: config.SirenType,
+		Volume:    strconv.Itoa(config.Volume),
+		Duration:  config.Duration,
+	}}
+	return h.executeHubMethod(ctx, "setSirenConfig", params, nil)
+}
+
+// GetAlarmStatus returns whether the siren is sounding. TimeLeft is only reported by the H200.
+func (h *Hub) GetAlarmStatus(ctx context.Context) (*SirenStatus, error) {
+	if h.snakeCase {
+		return h.getKlapAlarmStatus(ctx)
+	}
+	var response SirenStatus
+	err := h.executeHubMethod(ctx, "getSirenStatus", sirenParams{Siren: struct{}{}}, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// StartAlarm sounds the hub siren with the configured tone, volume and duration.
+func (h *Hub) StartAlarm(ctx context.Context) error {
+	if h.snakeCase {
+		return h.executeSingleMethod(ctx, "play_alarm", nil, nil)
+	}
+	params := sirenParams{Siren: map[string]string{"status": "on"}}
+	return h.executeHubMethod(ctx, "setSirenStatus", params, nil)
+}
+
+func (h *Hub) StopAlarm(ctx context.Context) error {
+	if h.snakeCase {
+		return h.executeSingleMethod(ctx, "stop_alarm", nil, nil)
+	}
+	params := sirenParams{Siren: map[string]string{"status": "off"}}
+	return h.executeHubMethod(ctx, "setSirenStatus", params, nil)
+}
+
+// ChildScanStatusScanning is the scan status reported while the hub is still looking for pairable children.
+const ChildScanStatusScanning = "scanning"
+
+// ScannedChildDevice is a pairable device found by a child scan.
+type ScannedChildDevice struct {
+	DeviceId    string `json:"device_id"`
+	Category    string `json:"category"`
+	DeviceModel string `json:"device_model"`
+	Name        string `json:"name"`
+
+	raw json.RawMessage
+}
+
+func (d *ScannedChildDevice) UnmarshalJSON(data []byte) error {
+	type scannedChildDevice ScannedChildDevice
+	var device scannedChildDevice
+	if err := json.Unmarshal(data, &device); err != nil {
+		return err
+	}
+	*d = ScannedChildDevice(device)
+	d.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON sends the device back to the hub exactly as the scan reported it,
+// because pairing needs fields the library does not model.
+func (d ScannedChildDevice) MarshalJSON() ([]byte, error) {
+	if d.raw != nil {
+		return d.raw, nil
+	}
+	type scannedChildDevice ScannedChildDevice
+	return json.Marshal(scannedChildDevice(d))
+}
+
+type ChildScanResult struct {
+	ScanStatus      string               `json:"scan_status"`
+	ScanWaitTime    int                  `json:"scan_wait_time"`
+	ChildDeviceList []ScannedChildDevice `json:"child_device_list"`
+}
+
+// IsScanning reports whether the hub is still looking for pairable children.
+func (r ChildScanResult) IsScanning() bool {
+	return r.ScanStatus == ChildScanStatusScanning
+}
+
+type childControlParams struct {
+	ChildControl any `json:"childControl"`
+}
+
+// GetSupportedChildCategories returns the child device categories the hub can pair.
+func (h *Hub) GetSupportedChildCategories(ctx context.Context) ([]string, error) {
+	var response struct {
+		DeviceCategoryList []struct {
+			Category string `json:"category"`
+		} `json:"device_category_list"`
+	}
+	params := childControlParams{ChildControl: map[string]int{"start_index": 0}}
+	if err := h.executeHubMethod(ctx, "getSupportChildDeviceCategory", params, &response); err != nil {
+		return nil, err
+	}
+	categories := make([]string, 0, len(response.DeviceCategoryList))
+	for _, c := range response.DeviceCategoryList {
+		categories = append(categories, c.Category)
+	}
+	return categories, nil
+}
+
+// StartChildScan makes the hub look for pairable children of the given categories.
+// If no categories are given, all categories supported by the hub are scanned.
+func (h *Hub) StartChildScan(ctx context.Context, categories ...string) error {
+	categories, err := h.scanCategories(ctx, categories)
+	if err != nil {
+		return err
+	}
+	params := childControlParams{ChildControl: map[string][]string{"category": categories}}
+	return h.executeHubMethod(ctx, "startScanChildDevice", params, nil)
+}
+
+// GetChildScanResults returns the devices found so far by a scan started with StartChildScan.
+func (h *Hub) GetChildScanResults(ctx context.Context, categories ...string) (*ChildScanResult, error) {
+	categories, err := h.scanCategories(ctx, categories)
+	if err != nil {
+		return nil, err
+	}
+	var response ChildScanResult
+	params := childControlParams{ChildControl: map[string][]string{"category": categories}}
+	if err = h.executeHubMethod(ctx, "getScanChildDeviceList", params, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ScanChildDevices starts a scan and polls its results every pollInterval until the hub finishes scanning.
+// If ctx expires first, the devices found so far are returned together with the context error.
+func (h *Hub) ScanChildDevices(ctx context.Context, pollInterval time.Duration, categories ...string) ([]ScannedChildDevice, error) {
+	if pollInterval <= 0 {
+		return nil, fmt.Errorf("poll interval must be positive: %s", pollInterval)
+	}
+	categories, err := h.scanCategories(ctx, categories)
+	if err != nil {
+		return nil, err
+	}
+	if err = h.StartChildScan(ctx, categories...); err != nil {
+		return nil, err
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	var found []ScannedChildDevice
+	for {
+		select {
+		case <-ctx.Done():
+			return found, ctx.Err()
+		case <-ticker.C:
+			result, err := h.GetChildScanResults(ctx, categories...)
+			if err != nil {
+				return found, err
+			}
+			found = result.ChildDeviceList
+			if !result.IsScanning() {
+				return found, nil
+			}
+		}
+	}
+}
+
+// PairChildDevices pairs devices found by a child scan with the hub.
+func (h *Hub) PairChildDevices(ctx context.Context, devices ...ScannedChildDevice) error {
+	if len(devices) == 0 {
+		return errors.New("no devices to pair")
+	}
+	params := childControlParams{ChildControl: map[string][]ScannedChildDevice{"child_device_list": devices}}
+	return h.executeHubMethod(ctx, "addScanChildDeviceList", params, nil)
+}
+
+// RemoveChildDevice unpairs the child device with the given id from the hub.
+func (h *Hub) RemoveChildDevice(ctx context.Context, deviceId string) error {
+	params := childControlParams{ChildControl: map[string][]map[string]string{
+		"child_device_list": {{"device_id": deviceId}},
+	}}
+	return h.executeHubMethod(ctx, "removeChildDeviceList", params, nil)
+}
+
+func (h *Hub) scanCategories(ctx context.Context, categories []string) ([]string, error) {
+	if len(categories) > 0 {
+		return categories, nil
+	}
+	return h.GetSupportedChildCategories(ctx)
+}
+
+// Categories reported by hub children.
+const (
+	MotionSensorCategory      = "subg.trigger.motion-sensor"
+	LeakSensorCategory        = "subg.trigger.water-leak-sensor"
+	TemperatureSensorCategory = "subg.trigger.temp-hmdt-sensor"
+	RemoteCategory            = "subg.trigger.button"
+	RadiatorValveCategory     = "subg.trv"
+)
+
+// ChildDevice is a decoded hub child device.
+type ChildDevice interface {
+	ChildInfo() *HubChildInfo
+}
+
+// ChildInfo returns the fields shared by all hub children.
+func (c *HubChildInfo) ChildInfo() *HubChildInfo {
+	return c
+}
+
+// UnknownChildDevice is a hub child of a model the library does not know. Raw holds its full entry.
+type UnknownChildDevice struct {
+	HubChildInfo
+	Raw json.RawMessage
+}
+
+// DecodeChildDevice decodes a single child device entry into its concrete type,
+// chosen by the entry's category or, if the category is not known, by its model.
+func DecodeChildDevice(entry json.RawMessage) (ChildDevice, error) {
+	var info HubChildInfo
+	if err := json.Unmarshal(entry, &info); err != nil {
+		return nil, err
+	}
+	var device ChildDevice
+	switch {
+	case info.Category == MotionSensorCategory || info.Model == "T100":
+		device = &MotionSensorInfo{}
+	case info.Category == LeakSensorCategory || info.Model == "T300":
+		device = &LeakSensorInfo{}
+	case info.Category == TemperatureSensorCategory || info.Model == "T310" || info.Model == "T315":
+		device = &TSeriesResponse{}
+	case info.Category == RadiatorValveCategory || info.Model == "KE100":
+		device = &RadiatorValveInfo{}
+	case info.Category == RemoteCategory || info.Model == "S200B" || info.Model == "S200D":
+		device = &RemoteInfo{}
+	case info.Category == SwitchCategory || info.Model == "S210" || info.Model == "S220":
+		device = &HubSwitchInfo{}
+	default:
+		return &UnknownChildDevice{HubChildInfo: info, Raw: append(json.RawMessage(nil), entry...)}, nil
+	}
+	if err := json.Unmarshal(entry, device); err != nil {
+		return nil, fmt.Errorf("error decoding %s child %s: %w", info.Model, info.DeviceId, err)
+	}
+	return device, nil
+}
@@ -0,0 +1,157 @@
+package tapo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type klapChildControlResult struct {
+	ResponseData hubMultipleResponse `json:"responseData"`
+}
+
+// executeKlapChildMethod sends request data to a child through control_child,
+// the KLAP equivalent of controlChild, and stores the child's responses in control.
+func (h *Hub) executeKlapChildMethod(ctx context.Context, deviceId string, requestData hubRequest, control *childControlResult) error {
+	params := map[string]any{
+		"device_id":   deviceId,
+		"requestData": requestData,
+	}
+	var response klapChildControlResult
+	if err := h.executeSingleMethod(ctx, "control_child", params, &response); err != nil {
+		return err
+	}
+	control.ResponseData = response.ResponseData
+	return nil
+}
+
+// getKlapChildDevicesPage returns a page of get_child_device_list in the shape the camelCase method set returns it.
+func (h *Hub) getKlapChildDevicesPage(ctx context.Context, startIndex int) (ChildDeviceListResponse, error) {
+	var response ChildDeviceListResponse
+	var body json.RawMessage
+	err := h.executeSingleMethod(ctx, "get_child_device_list", map[string]int{"start_index": startIndex}, &body)
+	if err != nil {
+		return response, err
+	}
+	if len(body) == 0 {
+		return response, errors.New("empty response for get_child_device_list")
+	}
+	var page ChildDeviceListPage
+	if err = json.Unmarshal(body, &page); err != nil {
+		return response, err
+	}
+	response.Result.Responses = []ChildDeviceListMethodResponse{{Method: "get_child_device_list", Result: page}}
+	return response, nil
+}
+
+// getKlapDeviceInfo returns get_device_info in the shape the camelCase method set returns it.
+func (h *Hub) getKlapDeviceInfo(ctx context.Context) (HubDeviceInfoResponse, error) {
+	var response HubDeviceInfoResponse
+	var raw singleMethodResponse
+	if err := h.ExecuteMethod(ctx, "get_device_info", nil, &raw); err != nil {
+		return response, err
+	}
+	response.ErrorCode = raw.ErrorCode
+	if raw.ErrorCode != 0 {
+		return response, nil
+	}
+	if len(raw.Result) == 0 {
+		return response, errors.New("empty response for get_device_info")
+	}
+	var info DeviceInfoResponse
+	if err := json.Unmarshal(raw.Result, &info.Result); err != nil {
+		return response, err
+	}
+	basicInfo := HubBasicInfo{
+		DeviceType:  info.Result.Type,
+		DeviceModel: info.Result.Model,
+		HwVersion:   info.Result.HwVer,
+		SwVersion:   info.Result.FwVer,
+		DeviceAlias: decodeNickname(info.Result.Nickname),
+		Mac:         info.Result.Mac,
+		DevId:       info.Result.DeviceId,
+		OemId:       info.Result.OemId,
+		HwId:        info.Result.HwId,
+		Avatar:      info.Result.Avatar,
+		Latitude:    info.Result.Latitude,
+		Longitude:   info.Result.Longitude,
+		Region:      info.Result.Region,
+		LocalIp:     info.Result.Ip,
+	}
+	if info.Result.HasSetLocationInfo {
+		basicInfo.HasSetLocationInfo = 1
+	}
+	method := HubDeviceInfoMethodResponse{Method: "get_device_info"}
+	method.Result.DeviceInfo = HubDeviceInfo{BasicInfo: basicInfo, Info: basicInfo}
+	response.Result.Responses = []HubDeviceInfoMethodResponse{method}
+	return response, nil
+}
+
+// AlarmVolume is a siren volume level of a hub speaking HubProtocolSnakeCase, such as the H100.
+type AlarmVolume int
+
+const (
+	AlarmVolumeLow AlarmVolume = iota + 1
+	AlarmVolumeNormal
+	AlarmVolumeHigh
+)
+
+// klapAlarmVolumes maps volume levels to the names the hub uses for them.
+var klapAlarmVolumes = map[AlarmVolume]string{
+	AlarmVolumeLow:    "low",
+	AlarmVolumeNormal: "normal",
+	AlarmVolumeHigh:   "high",
+}
+
+type klapAlarmConfig struct {
+	Type     string `json:"type"`
+	Volume   string `json:"volume"`
+	Duration int    `json:"duration"`
+}
+
+func (h *Hub) getKlapAlarmTones(ctx context.Context) ([]string, error) {
+	var response struct {
+		AlarmTypeList []string `json:"alarm_type_list"`
+	}
+	if err := h.executeSingleMethod(ctx, "get_support_alarm_type_list", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.AlarmTypeList, nil
+}
+
+func (h *Hub) getKlapAlarmConfig(ctx context.Context) (*SirenConfig, error) {
+	var response klapAlarmConfig
+	if err := h.executeSingleMethod(ctx, "get_alarm_configure", nil, &response); err != nil {
+		return nil, err
+	}
+	for volume, name := range klapAlarmVolumes {
+		if name == response.Volume {
+			return &SirenConfig{SirenType: response.Type, AlarmVolume: volume, Duration: response.Duration}, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected siren volume: %q", response.Volume)
+}
+
+func (h *Hub) setKlapAlarmConfig(ctx context.Context, config SirenConfig) error {
+	params := klapAlarmConfig{
+		Type:     config.SirenType,
+		Volume:   klapAlarmVolumes[config.AlarmVolume],
+		Duration: config.Duration,
+	}
+	return h.executeSingleMethod(ctx, "set_alarm_configure", params, nil)
+}
+
+// getKlapAlarmStatus reads the siren state from the hub's device info, which is where KLAP hubs report it.
+func (h *Hub) getKlapAlarmStatus(ctx context.Context) (*SirenStatus, error) {
+	var response struct {
+		InAlarm bool `json:"in_alarm"`
+	}
+	if err := h.executeSingleMethod(ctx, "get_device_info", nil, &response); err != nil {
+		return nil, err
+	}
+	if response.InAlarm {
+		return &SirenStatus{Status: "on"}, nil
+	}
+	return &SirenStatus{Status: "off"}, nil
+}
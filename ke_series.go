@@ -0,0 +1,101 @@
+package tapo
+
+import (
+	"context"
+	"fmt"
+)
+
+// RadiatorValve is a KE100 thermostatic radiator valve attached to a hub.
+type RadiatorValve struct {
+	hub      *Hub
+	deviceId string
+}
+
+func NewRadiatorValve(hub *Hub, deviceId string) *RadiatorValve {
+	return &RadiatorValve{hub: hub, deviceId: deviceId}
+}
+
+type RadiatorValveInfo struct {
+	HubChildInfo
+	TargetTemp        float64         `json:"target_temp"`
+	CurrentTemp       float64         `json:"current_temp"`
+	TempUnit          TemperatureUnit `json:"temp_unit"`
+	TempOffset        int             `json:"temp_offset"`
+	MinControlTemp    int             `json:"min_control_temp"`
+	MaxControlTemp    int             `json:"max_control_temp"`
+	FrostProtectionOn bool            `json:"frost_protection_on"`
+	ChildProtection   bool            `json:"child_protection"`
+	BatteryPercentage int             `json:"battery_percentage"`
+	TrvStates         []string        `json:"trv_states"`
+	Location          string          `json:"location"`
+}
+
+// Valid temperature offset calibration range, in degrees.
+const (
+	radiatorValveMinTempOffset = -10
+	radiatorValveMaxTempOffset = 10
+)
+
+func (r *RadiatorValve) DeviceId() string {
+	return r.deviceId
+}
+
+func (r *RadiatorValve) GetDeviceInfo(ctx context.Context) (*RadiatorValveInfo, error) {
+	var response RadiatorValveInfo
+	err := r.hub.ExecuteChildMethod(ctx, r.deviceId, "get_device_info", nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SetTargetTemperature sets the temperature the valve regulates to.
+// The value must lie within the valve's min and max control temperature.
+func (r *RadiatorValve) SetTargetTemperature(ctx context.Context, temperature float64) error {
+	info, err := r.GetDeviceInfo(ctx)
+	if err != nil {
+		return err
+	}
+	if temperature < float64(info.MinControlTemp) || temperature > float64(info.MaxControlTemp) {
+		return fmt.Errorf("target temperature %.1f is outside %d-%d", temperature, info.MinControlTemp, info.MaxControlTemp)
+	}
+	params := map[string]float64{"target_temp": temperature}
+	return r.setDeviceInfo(ctx, params)
+}
+
+// SetTemperatureLimits sets the range the target temperature can be set to.
+func (r *RadiatorValve) SetTemperatureLimits(ctx context.Context, minTemp, maxTemp int) error {
+	if minTemp >= maxTemp {
+		return fmt.Errorf("min temperature %d must be lower than max temperature %d", minTemp, maxTemp)
+	}
+	params := map[string]int{"min_control_temp": minTemp, "max_control_temp": maxTemp}
+	return r.setDeviceInfo(ctx, params)
+}
+
+// SetTemperatureOffset calibrates the valve's temperature reading by the given number of degrees.
+func (r *RadiatorValve) SetTemperatureOffset(ctx context.Context, offset int) error {
+	if offset < radiatorValveMinTempOffset || offset > radiatorValveMaxTempOffset {
+		return fmt.Errorf("temperature offset %d is outside %d-%d", offset, radiatorValveMinTempOffset, radiatorValveMaxTempOffset)
+	}
+	params := map[string]int{"temp_offset": offset}
+	return r.setDeviceInfo(ctx, params)
+}
+
+func (r *RadiatorValve) SetFrostProtection(ctx context.Context, enabled bool) error {
+	params := map[string]bool{"frost_protection_on": enabled}
+	return r.setDeviceInfo(ctx, params)
+}
+
+func (r *RadiatorValve) SetChildLock(ctx context.Context, enabled bool) error {
+	params := map[string]bool{"child_protection": enabled}
+	return r.setDeviceInfo(ctx, params)
+}
+
+// RemoveScale runs the maintenance routine that fully opens and closes the valve to free it from scale.
+func (r *RadiatorValve) RemoveScale(ctx context.Context) error {
+	return r.hub.ExecuteChildMethod(ctx, r.deviceId, "start_remove_scale", nil, nil)
+}
+
+func (r *RadiatorValve) setDeviceInfo(ctx context.Context, params any) error {
+	return r.hub.ExecuteChildMethod(ctx, r.deviceId, "set_device_info", params, nil)
+}
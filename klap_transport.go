@@ -183,6 +183,10 @@ func (k *KlapTransport) handshake(ctx context.Context) error {
 	return nil
 }
 
+func (k *KlapTransport) reconnect(ctx context.Context) error {
+	return k.handshake(ctx)
+}
+
 func (k *KlapTransport) ExecuteRequest(ctx context.Context, request *RequestSpec) (response json.RawMessage, err error) {
 	return ExecuteHttpRequest(ctx, k, request, k.retryConfig)
 }
@@ -0,0 +1,41 @@
+package tapo
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// SmartBulb is an L-series light such as the L510 or L530. Lights use the KLAP transport like the plugs.
+// Only switching the light on and off is supported, brightness and colour are not.
+type SmartBulb struct {
+	*Device
+}
+
+func NewSmartBulb(ctx context.Context, host, email, password string, options Options) (*SmartBulb, error) {
+	tr, err := NewKlapTransport(ctx, email, password, host, options)
+	if err != nil {
+		return nil, err
+	}
+	tapo := NewDevice(tr, options)
+	return &SmartBulb{tapo}, nil
+}
+
+func (b *SmartBulb) TurnOn(ctx context.Context) (*SetDeviceParameterResponse, error) {
+	var response *SetDeviceParameterResponse
+	params := json.RawMessage("{\"device_on\":true}")
+	err := b.ExecuteMethod(ctx, "set_device_info", params, &response)
+	return response, err
+}
+
+func (b *SmartBulb) TurnOff(ctx context.Context) (*SetDeviceParameterResponse, error) {
+	var response *SetDeviceParameterResponse
+	params := json.RawMessage("{\"device_on\":false}")
+	err := b.ExecuteMethod(ctx, "set_device_info", params, &response)
+	return response, err
+}
+
+func (b *SmartBulb) DeviceInfo(ctx context.Context) (*DeviceInfoResponse, error) {
+	var response *DeviceInfoResponse
+	err := b.ExecuteMethod(ctx, "get_device_info", nil, &response)
+	return response, err
+}
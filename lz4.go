@@ -0,0 +1,69 @@
+package tapo
+
+import "errors"
+
+// lz4DecompressBlock decompresses a raw LZ4 block, the format vacuums compress their maps with,
+// into a buffer of at most maxSize bytes.
+func lz4DecompressBlock(src []byte, maxSize int) ([]byte, error) {
+	dst := make([]byte, 0, maxSize)
+	for i := 0; i < len(src); {
+		token := src[i]
+		i++
+
+		literals := int(token >> 4)
+		if literals == 15 {
+			for {
+				if i >= len(src) {
+					return nil, errors.New("lz4: truncated literal length")
+				}
+				b := src[i]
+				i++
+				literals += int(b)
+				if b != 255 {
+					break
+				}
+			}
+		}
+		if i+literals > len(src) {
+			return nil, errors.New("lz4: truncated literals")
+		}
+		dst = append(dst, src[i:i+literals]...)
+		i += literals
+		if i == len(src) {
+			break
+		}
+
+		if i+2 > len(src) {
+			return nil, errors.New("lz4: truncated match offset")
+		}
+		offset := int(src[i]) | int(src[i+1])<<8
+		i += 2
+		if offset == 0 || offset > len(dst) {
+			return nil, errors.New("lz4: invalid match offset")
+		}
+
+		match := int(token&0x0f) + 4
+		if token&0x0f == 15 {
+			for {
+				if i >= len(src) {
+					return nil, errors.New("lz4: truncated match length")
+				}
+				b := src[i]
+				i++
+				match += int(b)
+				if b != 255 {
+					break
+				}
+			}
+		}
+		if len(dst)+match > maxSize {
+			return nil, errors.New("lz4: output exceeds expected size")
+		}
+		// Matches may overlap the bytes they produce, so copy byte by byte.
+		start := len(dst) - offset
+		for j := 0; j < match; j++ {
+			dst = append(dst, dst[start+j])
+		}
+	}
+	return dst, nil
+}
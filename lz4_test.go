@@ -0,0 +1,48 @@
+package tapo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLz4DecompressBlock(t *testing.T) {
+	longLiteral := strings.Repeat("x", 20)
+	tests := []struct {
+		name    string
+		src     []byte
+		maxSize int
+		want    string
+		wantErr bool
+	}{
+		{name: "literals only", src: append([]byte{0x50}, "hello"...), maxSize: 5, want: "hello"},
+		{name: "extended literal length", src: append([]byte{0xf0, 0x05}, longLiteral...), maxSize: 20, want: longLiteral},
+		{name: "overlapping match", src: []byte{0x15, 'a', 0x01, 0x00, 0x10, 'b'}, maxSize: 11, want: "aaaaaaaaaab"},
+		{name: "match copied from earlier bytes", src: []byte{0x30, 'a', 'b', 'c', 0x02, 0x00}, maxSize: 9, want: "abcbcbc"},
+		{name: "extended match length", src: []byte{0x1f, 'z', 0x01, 0x00, 0x01}, maxSize: 21, want: strings.Repeat("z", 21)},
+		{name: "empty block", src: []byte{}, maxSize: 0, want: ""},
+		{name: "truncated literals", src: []byte{0x50, 'h'}, maxSize: 5, wantErr: true},
+		{name: "truncated literal length", src: []byte{0xf0}, maxSize: 20, wantErr: true},
+		{name: "truncated offset", src: []byte{0x10, 'a', 0x01}, maxSize: 5, wantErr: true},
+		{name: "offset before start", src: []byte{0x10, 'a', 0x05, 0x00}, maxSize: 10, wantErr: true},
+		{name: "zero offset", src: []byte{0x10, 'a', 0x00, 0x00}, maxSize: 10, wantErr: true},
+		{name: "output exceeds size", src: []byte{0x15, 'a', 0x01, 0x00}, maxSize: 5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lz4DecompressBlock(tt.src, tt.maxSize)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(got, []byte(tt.want)) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package tapo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// VacuumStatus is the activity reported by a robot vacuum.
+type VacuumStatus int
+
+const (
+	VacuumStatusIdle      VacuumStatus = 0
+	VacuumStatusCleaning  VacuumStatus = 1
+	VacuumStatusMapping   VacuumStatus = 2
+	VacuumStatusGoingHome VacuumStatus = 4
+	VacuumStatusCharging  VacuumStatus = 5
+	VacuumStatusCharged   VacuumStatus = 6
+	VacuumStatusPaused    VacuumStatus = 7
+	VacuumStatusUndocked  VacuumStatus = 8
+	VacuumStatusError     VacuumStatus = 100
+)
+
+func (s VacuumStatus) String() string {
+	switch s {
+	case VacuumStatusIdle:
+		return "idle"
+	case VacuumStatusCleaning:
+		return "cleaning"
+	case VacuumStatusMapping:
+		return "mapping"
+	case VacuumStatusGoingHome:
+		return "going home"
+	case VacuumStatusCharging:
+		return "charging"
+	case VacuumStatusCharged:
+		return "charged"
+	case VacuumStatusPaused:
+		return "paused"
+	case VacuumStatusUndocked:
+		return "undocked"
+	case VacuumStatusError:
+		return "error"
+	}
+	return fmt.Sprintf("unknown (%d)", int(s))
+}
+
+// SuctionPower is the fan speed of a robot vacuum.
+type SuctionPower int
+
+const (
+	SuctionQuiet    SuctionPower = 1
+	SuctionStandard SuctionPower = 2
+	SuctionTurbo    SuctionPower = 3
+	SuctionMax      SuctionPower = 4
+	SuctionUltra    SuctionPower = 5
+)
+
+// WaterLevel is the amount of water a robot vacuum with a mop uses.
+type WaterLevel int
+
+const (
+	WaterLevelOff    WaterLevel = 0
+	WaterLevelLow    WaterLevel = 1
+	WaterLevelMedium WaterLevel = 2
+	WaterLevelHigh   WaterLevel = 3
+)
+
+// Consumable identifies a wearing part of a robot vacuum by the field the vacuum reports its usage in.
+type Consumable string
+
+const (
+	ConsumableMainBrush       Consumable = "roll_brush_time"
+	ConsumableSideBrush       Consumable = "edge_brush_time"
+	ConsumableFilter          Consumable = "filter_time"
+	ConsumableMop             Consumable = "mop_time"
+	ConsumableSensor          Consumable = "sensor_time"
+	ConsumableChargingContact Consumable = "charge_contact_time"
+)
+
+// consumableLifetimes are the recommended replacement intervals of the consumables.
+var consumableLifetimes = map[Consumable]time.Duration{
+	ConsumableMainBrush:       400 * time.Hour,
+	ConsumableSideBrush:       200 * time.Hour,
+	ConsumableFilter:          200 * time.Hour,
+	ConsumableMop:             100 * time.Hour,
+	ConsumableSensor:          30 * time.Hour,
+	ConsumableChargingContact: 30 * time.Hour,
+}
+
+// Vacuum is an RV10, RV20 or RV30 robot vacuum. Vacuums use the SSL transport on port 4433.
+type Vacuum struct {
+	*Device
+}
+
+func NewVacuum(ctx context.Context, host, email, password string, options Options) (*Vacuum, error) {
+	tr, err := NewSslTransport(ctx, email, password, host, options)
+	if err != nil {
+		return nil, err
+	}
+	tapo := NewDevice(tr, options)
+	return &Vacuum{tapo}, nil
+}
+
+// VacuumState is the current activity of the vacuum. ErrorCodes is empty unless the vacuum is stuck.
+type VacuumState struct {
+	Status     VacuumStatus `json:"status"`
+	ErrorCodes []int        `json:"err_status"`
+}
+
+type CleanSettings struct {
+	Suction     SuctionPower `json:"suction"`
+	WaterLevel  WaterLevel   `json:"cistern"`
+	CleanNumber int          `json:"clean_number"`
+}
+
+// ConsumableUsage is how long a consumable has been used and how long it is meant to last.
+type ConsumableUsage struct {
+	Consumable Consumable
+	Used       time.Duration
+	Lifetime   time.Duration
+}
+
+// Remaining returns the share of the consumable's lifetime left, between 0 and 1.
+func (c ConsumableUsage) Remaining() float64 {
+	if c.Lifetime == 0 || c.Used >= c.Lifetime {
+		return 0
+	}
+	return 1 - float64(c.Used)/float64(c.Lifetime)
+}
+
+func (v *Vacuum) DeviceInfo(ctx context.Context) (*DeviceInfoResponse, error) {
+	var response *DeviceInfoResponse
+	err := v.ExecuteMethod(ctx, "get_device_info", nil, &response)
+	return response, err
+}
+
+func (v *Vacuum) GetState(ctx context.Context) (*VacuumState, error) {
+	var response VacuumState
+	if err := v.executeSingleMethod(ctx, "get_vac_status", nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (v *Vacuum) GetBatteryLevel(ctx context.Context) (int, error) {
+	var response struct {
+		BatteryPercentage int `json:"battery_percentage"`
+	}
+	if err := v.executeSingleMethod(ctx, "get_battery_info", nil, &response); err != nil {
+		return 0, err
+	}
+	return response.BatteryPercentage, nil
+}
+
+// Start starts cleaning the whole home.
+func (v *Vacuum) Start(ctx context.Context) error {
+	params := map[string]any{
+		"clean_mode":  0,
+		"clean_on":    true,
+		"clean_order": true,
+		"force_clean": false,
+	}
+	return v.executeSingleMethod(ctx, "set_switch_clean", params, nil)
+}
+
+func (v *Vacuum) Pause(ctx context.Context) error {
+	return v.executeSingleMethod(ctx, "set_pause", map[string]bool{"pause": true}, nil)
+}
+
+func (v *Vacuum) Resume(ctx context.Context) error {
+	return v.executeSingleMethod(ctx, "set_pause", map[string]bool{"pause": false}, nil)
+}
+
+func (v *Vacuum) ReturnToDock(ctx context.Context) error {
+	return v.executeSingleMethod(ctx, "set_switch_charge", map[string]bool{"switch_charge": true}, nil)
+}
+
+func (v *Vacuum) GetCleanSettings(ctx context.Context) (*CleanSettings, error) {
+	var response CleanSettings
+	if err := v.executeSingleMethod(ctx, "get_clean_attr", map[string]string{"type": "global"}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (v *Vacuum) SetSuctionPower(ctx context.Context, suction SuctionPower) error {
+	if suction < SuctionQuiet || suction > SuctionUltra {
+		return fmt.Errorf("unsupported suction power: %d", suction)
+	}
+	params := map[string]any{"suction": suction, "type": "global"}
+	return v.executeSingleMethod(ctx, "set_clean_attr", params, nil)
+}
+
+func (v *Vacuum) SetWaterLevel(ctx context.Context, level WaterLevel) error {
+	if level < WaterLevelOff || level > WaterLevelHigh {
+		return fmt.Errorf("unsupported water level: %d", level)
+	}
+	params := map[string]any{"cistern": level, "type": "global"}
+	return v.executeSingleMethod(ctx, "set_clean_attr", params, nil)
+}
+
+// GetConsumables returns the usage of every consumable the vacuum reports.
+func (v *Vacuum) GetConsumables(ctx context.Context) ([]ConsumableUsage, error) {
+	var response map[Consumable]int
+	if err := v.executeSingleMethod(ctx, "get_consumables_info", nil, &response); err != nil {
+		return nil, err
+	}
+	usage := make([]ConsumableUsage, 0, len(consumableLifetimes))
+	for _, consumable := range []Consumable{
+		ConsumableMainBrush, ConsumableSideBrush, ConsumableFilter,
+		ConsumableMop, ConsumableSensor, ConsumableChargingContact,
+	} {
+		minutes, ok := response[consumable]
+		if !ok {
+			continue
+		}
+		usage = append(usage, ConsumableUsage{
+			Consumable: consumable,
+			Used:       time.Duration(minutes) * time.Minute,
+			Lifetime:   consumableLifetimes[consumable],
+		})
+	}
+	return usage, nil
+}
+
+// ResetConsumable resets the usage of a consumable after it has been replaced.
+func (v *Vacuum) ResetConsumable(ctx context.Context, consumable Consumable) error {
+	if _, ok := consumableLifetimes[consumable]; !ok {
+		return fmt.Errorf("unsupported consumable: %q", consumable)
+	}
+	params := map[string][]Consumable{"reset_list": {consumable}}
+	return v.executeSingleMethod(ctx, "reset_consumable", params, nil)
+}
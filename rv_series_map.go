@@ -0,0 +1,336 @@
+package tapo
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// Values of map cells that do not belong to a room. Cells of a room hold the room's segment id.
+const (
+	MapCellUnknown = 0
+	MapCellWall    = 127
+	MapCellFloor   = 128
+)
+
+// Types of the areas a vacuum map contains.
+const (
+	mapAreaRoom = "area"
+	mapAreaNoGo = "forbid"
+)
+
+// Values of type in get_map_data: the map as stored, or the live view the vacuum is
+// building while it cleans, which carries its current position.
+const (
+	mapTypeSaved = 0
+	mapTypeLive  = 1
+)
+
+// MapSummary describes a map stored on the vacuum.
+type MapSummary struct {
+	MapId   int    `json:"map_id"`
+	MapName string `json:"map_name"`
+	IsSaved bool   `json:"is_saved"`
+}
+
+type mapInfo struct {
+	CurrentMapId int          `json:"current_map_id"`
+	MapList      []MapSummary `json:"map_list"`
+	MapNum       int          `json:"map_num"`
+}
+
+type mapArea struct {
+	Id      int      `json:"id"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Vertexs [][2]int `json:"vertexs"`
+}
+
+type mapData struct {
+	MapId          int       `json:"map_id"`
+	Width          int       `json:"width"`
+	Height         int       `json:"height"`
+	Resolution     int       `json:"resolution"`
+	OriginCoor     []int     `json:"origin_coor"`
+	MapData        string    `json:"map_data"`
+	AreaList       []mapArea `json:"area_list"`
+	RealChargeCoor []int     `json:"real_charge_coor"`
+	RealVacCoor    []int     `json:"real_vac_coor"`
+}
+
+// MapRoom is a room segment of a vacuum map. Cells of the room hold its Id.
+type MapRoom struct {
+	Id   int
+	Name string
+}
+
+// MapZone is a polygon on the map, such as a no-go zone, in grid coordinates.
+type MapZone struct {
+	Id       int
+	Vertices []image.Point
+}
+
+// VacuumMap is a decoded vacuum map. Grid holds Width*Height cells row by row, starting at the top left;
+// every cell is MapCellUnknown, MapCellWall, MapCellFloor or the id of the room it belongs to.
+// Positions are in grid coordinates, with a negative X for positions the vacuum did not report.
+type VacuumMap struct {
+	MapId         int
+	Width         int
+	Height        int
+	Resolution    int
+	Grid          []byte
+	Rooms         []MapRoom
+	NoGoZones     []MapZone
+	Dock          image.Point
+	VacuumPos     image.Point
+	originX       int
+	originY       int
+	roomIdsByName map[string]int
+}
+
+// GetMaps returns the current map id and every map stored on the vacuum.
+func (v *Vacuum) GetMaps(ctx context.Context) (int, []MapSummary, error) {
+	var response mapInfo
+	if err := v.executeSingleMethod(ctx, "get_map_info", nil, &response); err != nil {
+		return 0, nil, err
+	}
+	for i := range response.MapList {
+		response.MapList[i].MapName = decodeNickname(response.MapList[i].MapName)
+	}
+	return response.CurrentMapId, response.MapList, nil
+}
+
+// GetCurrentMap returns the live view of the map the vacuum is currently cleaning with,
+// including the vacuum's current position.
+func (v *Vacuum) GetCurrentMap(ctx context.Context) (*VacuumMap, error) {
+	currentMapId, _, err := v.GetMaps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return v.getMap(ctx, currentMapId, mapTypeLive)
+}
+
+// GetMap fetches and decodes the saved map with the given id. Its vacuum position is where
+// the vacuum was when the map was last stored.
+func (v *Vacuum) GetMap(ctx context.Context, mapId int) (*VacuumMap, error) {
+	return v.getMap(ctx, mapId, mapTypeSaved)
+}
+
+func (v *Vacuum) getMap(ctx context.Context, mapId, mapType int) (*VacuumMap, error) {
+	var response mapData
+	params := map[string]int{"map_id": mapId, "type": mapType}
+	if err := v.executeSingleMethod(ctx, "get_map_data", params, &response); err != nil {
+		return nil, err
+	}
+	return decodeVacuumMap(&response)
+}
+
+// CleanRooms starts cleaning the rooms with the given names on the current map.
+func (v *Vacuum) CleanRooms(ctx context.Context, names ...string) error {
+	m, err := v.GetCurrentMap(ctx)
+	if err != nil {
+		return err
+	}
+	roomIds := make([]int, 0, len(names))
+	for _, name := range names {
+		id, ok := m.RoomId(name)
+		if !ok {
+			return fmt.Errorf("room %q not found on map %d", name, m.MapId)
+		}
+		roomIds = append(roomIds, id)
+	}
+	if len(roomIds) == 0 {
+		return fmt.Errorf("no rooms to clean")
+	}
+	params := map[string]any{
+		"clean_mode":  3,
+		"clean_on":    true,
+		"clean_order": true,
+		"force_clean": false,
+		"map_id":      m.MapId,
+		"room_list":   roomIds,
+		"start_type":  1,
+	}
+	return v.executeSingleMethod(ctx, "set_switch_clean", params, nil)
+}
+
+// RoomId returns the segment id of the room with the given name, ignoring case.
+func (m *VacuumMap) RoomId(name string) (int, bool) {
+	id, ok := m.roomIdsByName[strings.ToLower(name)]
+	return id, ok
+}
+
+func decodeVacuumMap(data *mapData) (*VacuumMap, error) {
+	if data.Width <= 0 || data.Height <= 0 || data.Resolution <= 0 {
+		return nil, fmt.Errorf("invalid map size %dx%d at resolution %d", data.Width, data.Height, data.Resolution)
+	}
+	compressed, err := base64.StdEncoding.DecodeString(data.MapData)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding map data: %w", err)
+	}
+	grid, err := lz4DecompressBlock(compressed, data.Width*data.Height)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing map data: %w", err)
+	}
+	if len(grid) != data.Width*data.Height {
+		return nil, fmt.Errorf("map data has %d cells, expected %d", len(grid), data.Width*data.Height)
+	}
+
+	m := &VacuumMap{
+		MapId:         data.MapId,
+		Width:         data.Width,
+		Height:        data.Height,
+		Resolution:    data.Resolution,
+		Grid:          grid,
+		roomIdsByName: make(map[string]int),
+	}
+	if len(data.OriginCoor) >= 2 {
+		m.originX, m.originY = data.OriginCoor[0], data.OriginCoor[1]
+	}
+	m.Dock = m.toGrid(data.RealChargeCoor)
+	m.VacuumPos = m.toGrid(data.RealVacCoor)
+
+	for _, area := range data.AreaList {
+		switch area.Type {
+		case mapAreaRoom:
+			room := MapRoom{Id: area.Id, Name: decodeNickname(area.Name)}
+			m.Rooms = append(m.Rooms, room)
+			m.roomIdsByName[strings.ToLower(room.Name)] = room.Id
+		case mapAreaNoGo:
+			zone := MapZone{Id: area.Id}
+			for _, vertex := range area.Vertexs {
+				zone.Vertices = append(zone.Vertices, m.toGrid(vertex[:]))
+			}
+			m.NoGoZones = append(m.NoGoZones, zone)
+		}
+	}
+	return m, nil
+}
+
+// toGrid converts real coordinates in millimetres to grid coordinates. The grid's rows run
+// top to bottom while the vacuum's y axis points up.
+func (m *VacuumMap) toGrid(coordinates []int) image.Point {
+	if len(coordinates) < 2 {
+		return image.Point{X: -1, Y: -1}
+	}
+	x := (coordinates[0] - m.originX) / m.Resolution
+	y := (coordinates[1] - m.originY) / m.Resolution
+	return image.Point{X: x, Y: m.Height - 1 - y}
+}
+
+// Cell returns the value of the cell at x, y, or MapCellUnknown outside the map.
+func (m *VacuumMap) Cell(x, y int) byte {
+	if x < 0 || y < 0 || x >= m.Width || y >= m.Height {
+		return MapCellUnknown
+	}
+	return m.Grid[y*m.Width+x]
+}
+
+var (
+	mapUnknownColor = color.RGBA{A: 0}
+	mapWallColor    = color.RGBA{R: 64, G: 64, B: 64, A: 255}
+	mapFloorColor   = color.RGBA{R: 200, G: 200, B: 200, A: 255}
+	mapNoGoColor    = color.RGBA{R: 230, G: 60, B: 60, A: 255}
+	mapDockColor    = color.RGBA{G: 170, A: 255}
+	mapVacuumColor  = color.RGBA{B: 220, A: 255}
+	mapRoomColors   = []color.RGBA{
+		{R: 141, G: 211, B: 199, A: 255},
+		{R: 255, G: 255, B: 179, A: 255},
+		{R: 190, G: 186, B: 218, A: 255},
+		{R: 251, G: 128, B: 114, A: 255},
+		{R: 128, G: 177, B: 211, A: 255},
+		{R: 253, G: 180, B: 98, A: 255},
+		{R: 179, G: 222, B: 105, A: 255},
+		{R: 252, G: 205, B: 229, A: 255},
+	}
+)
+
+// Image renders the map with every room in its own colour, no-go zone outlines,
+// the dock and the vacuum position.
+func (m *VacuumMap) Image() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, m.Width, m.Height))
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			img.SetRGBA(x, y, cellColor(m.Cell(x, y)))
+		}
+	}
+	for _, zone := range m.NoGoZones {
+		for i := range zone.Vertices {
+			drawLine(img, zone.Vertices[i], zone.Vertices[(i+1)%len(zone.Vertices)], mapNoGoColor)
+		}
+	}
+	drawMarker(img, m.Dock, mapDockColor)
+	drawMarker(img, m.VacuumPos, mapVacuumColor)
+	return img
+}
+
+// WritePNG renders the map and writes it to w as a PNG image.
+func (m *VacuumMap) WritePNG(w io.Writer) error {
+	return png.Encode(w, m.Image())
+}
+
+func cellColor(cell byte) color.RGBA {
+	switch cell {
+	case MapCellUnknown:
+		return mapUnknownColor
+	case MapCellWall:
+		return mapWallColor
+	case MapCellFloor:
+		return mapFloorColor
+	}
+	return mapRoomColors[int(cell)%len(mapRoomColors)]
+}
+
+func drawMarker(img *image.RGBA, p image.Point, c color.RGBA) {
+	if p.X < 0 {
+		return
+	}
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			if image.Pt(p.X+dx, p.Y+dy).In(img.Rect) {
+				img.SetRGBA(p.X+dx, p.Y+dy, c)
+			}
+		}
+	}
+}
+
+func drawLine(img *image.RGBA, from, to image.Point, c color.RGBA) {
+	dx, dy := abs(to.X-from.X), -abs(to.Y-from.Y)
+	sx, sy := 1, 1
+	if from.X > to.X {
+		sx = -1
+	}
+	if from.Y > to.Y {
+		sy = -1
+	}
+	errorTerm := dx + dy
+	for p := from; ; {
+		if p.In(img.Rect) {
+			img.SetRGBA(p.X, p.Y, c)
+		}
+		if p == to {
+			return
+		}
+		e2 := 2 * errorTerm
+		if e2 >= dy {
+			errorTerm += dy
+			p.X += sx
+		}
+		if e2 <= dx {
+			errorTerm += dx
+			p.Y += sy
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,97 @@
+package tapo
+
+import (
+	"image"
+	"slices"
+	"testing"
+)
+
+// testMapData is a 12x10 grid compressed with the lz4 tool: 30 unknown cells, 10 wall, 40 floor,
+// 25 of room 3, 5 wall and 10 unknown.
+const testMapData = "HwABAAoVfwEAH4ABABQfAwEABQFKAKAAAAAAAAAAAAAA"
+
+func TestDecodeVacuumMap(t *testing.T) {
+	valid := mapData{
+		MapId:          7,
+		Width:          12,
+		Height:         10,
+		Resolution:     50,
+		OriginCoor:     []int{-300, -250},
+		MapData:        testMapData,
+		RealChargeCoor: []int{0, 0},
+		AreaList: []mapArea{
+			{Id: 3, Name: encodeNickname("Kitchen"), Type: mapAreaRoom},
+			{Id: 9, Type: mapAreaNoGo, Vertexs: [][2]int{{-300, -250}, {200, 200}}},
+		},
+	}
+	withSize := func(width, height, resolution int) mapData {
+		data := valid
+		data.Width, data.Height, data.Resolution = width, height, resolution
+		return data
+	}
+	withMapData := func(mapData string) mapData {
+		data := valid
+		data.MapData = mapData
+		return data
+	}
+
+	tests := []struct {
+		name    string
+		data    mapData
+		wantErr bool
+	}{
+		{name: "valid map", data: valid},
+		{name: "zero width", data: withSize(0, 10, 50), wantErr: true},
+		{name: "zero resolution", data: withSize(12, 10, 0), wantErr: true},
+		{name: "fewer cells than the size", data: withSize(12, 11, 50), wantErr: true},
+		{name: "more cells than the size", data: withSize(12, 9, 50), wantErr: true},
+		{name: "invalid base64", data: withMapData("not base64!"), wantErr: true},
+		{name: "invalid lz4", data: withMapData("EGEFAA=="), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := decodeVacuumMap(&tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			cells := []struct {
+				x, y int
+				want byte
+			}{
+				{0, 0, MapCellUnknown},
+				{6, 2, MapCellWall},
+				{4, 3, MapCellFloor},
+				{0, 7, 3},
+				{9, 8, MapCellWall},
+				{11, 9, MapCellUnknown},
+				{12, 0, MapCellUnknown},
+			}
+			for _, c := range cells {
+				if got := m.Cell(c.x, c.y); got != c.want {
+					t.Errorf("Cell(%d, %d) = %d, want %d", c.x, c.y, got, c.want)
+				}
+			}
+			if m.MapId != 7 {
+				t.Errorf("MapId = %d, want 7", m.MapId)
+			}
+			if want := image.Pt(6, 4); m.Dock != want {
+				t.Errorf("Dock = %v, want %v", m.Dock, want)
+			}
+			if want := image.Pt(-1, -1); m.VacuumPos != want {
+				t.Errorf("VacuumPos = %v, want %v", m.VacuumPos, want)
+			}
+			if id, ok := m.RoomId("kitchen"); !ok || id != 3 {
+				t.Errorf("RoomId(kitchen) = %d, %t, want 3, true", id, ok)
+			}
+			if len(m.NoGoZones) != 1 || !slices.Equal(m.NoGoZones[0].Vertices, []image.Point{{0, 9}, {10, 0}}) {
+				t.Errorf("NoGoZones = %v, want one zone from (0,9) to (10,0)", m.NoGoZones)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package tapo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// RemoteEventType is the kind of action recorded by an S200B button or S200D dimmer remote.
+type RemoteEventType string
+
+const (
+	RemoteEventSingleClick RemoteEventType = "singleClick"
+	RemoteEventDoubleClick RemoteEventType = "doubleClick"
+	RemoteEventRotation    RemoteEventType = "rotation"
+)
+
+// remoteLogsPageSize is the number of trigger logs requested per page when looking for new events.
+const remoteLogsPageSize = 10
+
+// Remote is an S200B smart button or S200D dimmer remote attached to a hub.
+type Remote struct {
+	hub      *Hub
+	deviceId string
+}
+
+func NewRemote(hub *Hub, deviceId string) *Remote {
+	return &Remote{hub: hub, deviceId: deviceId}
+}
+
+// RemoteEvent is a decoded trigger log entry of a remote.
+// RotationDegrees is only set for rotation events; it is negative for anticlockwise rotation.
+type RemoteEvent struct {
+	Id              int
+	Type            RemoteEventType
+	RotationDegrees int
+	Time            time.Time
+}
+
+type RemoteInfo struct {
+	HubChildInfo
+}
+
+type remoteEventParams struct {
+	RotateDeg int `json:"rotate_deg"`
+}
+
+func (r *Remote) DeviceId() string {
+	return r.deviceId
+}
+
+func (r *Remote) GetDeviceInfo(ctx context.Context) (*RemoteInfo, error) {
+	var response RemoteInfo
+	err := r.hub.ExecuteChildMethod(ctx, r.deviceId, "get_device_info", nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetTriggerLogs returns up to pageSize raw trigger logs, newest first, starting from startId.
+func (r *Remote) GetTriggerLogs(ctx context.Context, pageSize, startId int) (*TriggerLogsResponse, error) {
+	return r.hub.GetTriggerLogs(ctx, r.deviceId, pageSize, startId)
+}
+
+// GetEvents returns up to pageSize decoded events, newest first.
+func (r *Remote) GetEvents(ctx context.Context, pageSize int) ([]RemoteEvent, error) {
+	logs, err := r.GetTriggerLogs(ctx, pageSize, 0)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]RemoteEvent, 0, len(logs.Logs))
+	for _, l := range logs.Logs {
+		event, err := DecodeRemoteEvent(l)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// GetEventsSince returns the events recorded after the log with id lastId, oldest first.
+// Pass the id of the last returned event on the next call to receive only new events.
+func (r *Remote) GetEventsSince(ctx context.Context, lastId int) ([]RemoteEvent, error) {
+	var events []RemoteEvent
+	startId := 0
+	for {
+		logs, err := r.GetTriggerLogs(ctx, remoteLogsPageSize, startId)
+		if err != nil {
+			return nil, err
+		}
+		done := false
+		added := 0
+		for _, l := range logs.Logs {
+			if l.Id <= lastId {
+				done = true
+				break
+			}
+			if startId != 0 && l.Id >= startId {
+				continue
+			}
+			event, err := DecodeRemoteEvent(l)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, event)
+			startId = l.Id
+			added++
+		}
+		if done || added == 0 || len(events) >= logs.Sum {
+			break
+		}
+	}
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	return events, nil
+}
+
+// DecodeRemoteEvent converts a raw trigger log of a remote into a typed event.
+func DecodeRemoteEvent(log TriggerLog) (RemoteEvent, error) {
+	event := RemoteEvent{
+		Id:   log.Id,
+		Type: RemoteEventType(log.Event),
+		Time: log.Time(),
+	}
+	if event.Type == RemoteEventRotation && len(log.Params) > 0 {
+		var params remoteEventParams
+		if err := json.Unmarshal(log.Params, &params); err != nil {
+			return event, fmt.Errorf("error decoding rotation event %d: %w", log.Id, err)
+		}
+		event.RotationDegrees = params.RotateDeg
+	}
+	return event, nil
+}
+
+// SwitchCategory is the category hub children report for S210 and S220 wall switches.
+const SwitchCategory = "subg.plugswitch.switch"
+
+// LedRule controls when the status LED of a device is lit.
+type LedRule string
+
+const (
+	LedRuleAlways    LedRule = "always"
+	LedRuleNever     LedRule = "never"
+	LedRuleNightMode LedRule = "night_mode"
+)
+
+// HubSwitch is a single gang of an S210 or S220 wall switch attached to a hub.
+// Multi-gang switches report every gang as its own child device.
+type HubSwitch struct {
+	hub      *Hub
+	deviceId string
+}
+
+func NewHubSwitch(hub *Hub, deviceId string) *HubSwitch {
+	return &HubSwitch{hub: hub, deviceId: deviceId}
+}
+
+type HubSwitchInfo struct {
+	HubChildInfo
+	DeviceOn   bool `json:"device_on"`
+	OnTime     int  `json:"on_time"`
+	Position   int  `json:"position"`
+	SlotNumber int  `json:"slot_number"`
+}
+
+type LedInfo struct {
+	LedRule   LedRule `json:"led_rule"`
+	LedStatus bool    `json:"led_status"`
+}
+
+// GetHubSwitches returns a switch for every S210 and S220 gang attached to the hub.
+func GetHubSwitches(ctx context.Context, hub *Hub) ([]*HubSwitch, error) {
+	devices, err := hub.GetChildDeviceList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switches := make([]*HubSwitch, 0)
+	for _, d := range devices {
+		if info, ok := d.(*HubSwitchInfo); ok {
+			switches = append(switches, NewHubSwitch(hub, info.DeviceId))
+		}
+	}
+	return switches, nil
+}
+
+func (s *HubSwitch) DeviceId() string {
+	return s.deviceId
+}
+
+func (s *HubSwitch) GetDeviceInfo(ctx context.Context) (*HubSwitchInfo, error) {
+	var response HubSwitchInfo
+	err := s.hub.ExecuteChildMethod(ctx, s.deviceId, "get_device_info", nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// IsOn reports whether the gang is currently switched on.
+func (s *HubSwitch) IsOn(ctx context.Context) (bool, error) {
+	info, err := s.GetDeviceInfo(ctx)
+	if err != nil {
+		return false, err
+	}
+	return info.DeviceOn, nil
+}
+
+func (s *HubSwitch) TurnOn(ctx context.Context) error {
+	params := map[string]bool{"device_on": true}
+	return s.hub.ExecuteChildMethod(ctx, s.deviceId, "set_device_info", params, nil)
+}
+
+func (s *HubSwitch) TurnOff(ctx context.Context) error {
+	params := map[string]bool{"device_on": false}
+	return s.hub.ExecuteChildMethod(ctx, s.deviceId, "set_device_info", params, nil)
+}
+
+func (s *HubSwitch) GetLedInfo(ctx context.Context) (*LedInfo, error) {
+	var response LedInfo
+	err := s.hub.ExecuteChildMethod(ctx, s.deviceId, "get_led_info", nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (s *HubSwitch) SetLedRule(ctx context.Context, rule LedRule) error {
+	switch rule {
+	case LedRuleAlways, LedRuleNever, LedRuleNightMode:
+	default:
+		return fmt.Errorf("unsupported led rule: %q", rule)
+	}
+	params := LedInfo{LedRule: rule, LedStatus: rule != LedRuleNever}
+	return s.hub.ExecuteChildMethod(ctx, s.deviceId, "set_led_info", params, nil)
+}
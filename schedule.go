@@ -0,0 +1,229 @@
+package tapo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ScheduleTrigger is what starts a schedule rule: a time of day, sunrise or sunset.
+type ScheduleTrigger string
+
+const (
+	ScheduleTriggerTime    ScheduleTrigger = "normal"
+	ScheduleTriggerSunrise ScheduleTrigger = "sunrise"
+	ScheduleTriggerSunset  ScheduleTrigger = "sunset"
+)
+
+// ScheduleMode is whether a rule repeats on its weekdays or runs once on its date.
+type ScheduleMode string
+
+const (
+	ScheduleModeRepeat ScheduleMode = "repeat"
+	ScheduleModeOnce   ScheduleMode = "once"
+)
+
+// Maximum offset of a sunrise or sunset rule, in minutes.
+const maxSunOffsetMinutes = 720
+
+// WeekdayMask is a set of weekdays, with bit 0 for Sunday as in time.Weekday.
+type WeekdayMask uint8
+
+// EveryDay is the mask of all seven weekdays.
+const EveryDay WeekdayMask = 0x7f
+
+func NewWeekdayMask(days ...time.Weekday) WeekdayMask {
+	var mask WeekdayMask
+	for _, day := range days {
+		mask |= 1 << uint(day)
+	}
+	return mask
+}
+
+func (m WeekdayMask) Has(day time.Weekday) bool {
+	return m&(1<<uint(day)) != 0
+}
+
+// DesiredStates is the state a rule puts the device into. Brightness only applies to lights.
+type DesiredStates struct {
+	On         bool `json:"on"`
+	Brightness int  `json:"brightness,omitempty"`
+}
+
+// ScheduleRule is a schedule stored on the device. StartMinute is the minute of the day for time rules;
+// for sunrise and sunset rules TimeOffset shifts the trigger by the given number of minutes.
+type ScheduleRule struct {
+	Id            string          `json:"id,omitempty"`
+	Enable        bool            `json:"enable"`
+	Mode          ScheduleMode    `json:"mode"`
+	StartType     ScheduleTrigger `json:"s_type"`
+	StartMinute   int             `json:"s_min"`
+	TimeOffset    int             `json:"time_offset"`
+	EndType       string          `json:"e_type"`
+	EndMinute     int             `json:"e_min"`
+	EndAction     string          `json:"e_action"`
+	WeekDays      WeekdayMask     `json:"week_day"`
+	Day           int             `json:"day,omitempty"`
+	Month         int             `json:"month,omitempty"`
+	Year          int             `json:"year,omitempty"`
+	DesiredStates DesiredStates   `json:"desired_states"`
+}
+
+type ScheduleRules struct {
+	Enable   bool
+	MaxCount int
+	Rules    []ScheduleRule
+}
+
+type scheduleRulesPage struct {
+	Enable               bool           `json:"enable"`
+	RuleList             []ScheduleRule `json:"rule_list"`
+	ScheduleRuleMaxCount int            `json:"schedule_rule_max_count"`
+	StartIndex           int            `json:"start_index"`
+	Sum                  int            `json:"sum"`
+}
+
+// NewTimeScheduleRule returns a rule that sets the device on or off at hour:minute on the given weekdays.
+func NewTimeScheduleRule(hour, minute int, days WeekdayMask, on bool) ScheduleRule {
+	return ScheduleRule{
+		Enable:        true,
+		Mode:          ScheduleModeRepeat,
+		StartType:     ScheduleTriggerTime,
+		StartMinute:   hour*60 + minute,
+		EndType:       "normal",
+		EndAction:     "none",
+		WeekDays:      days,
+		DesiredStates: DesiredStates{On: on},
+	}
+}
+
+// NewSunScheduleRule returns a rule that sets the device on or off at sunrise or sunset,
+// shifted by offsetMinutes, on the given weekdays.
+func NewSunScheduleRule(trigger ScheduleTrigger, offsetMinutes int, days WeekdayMask, on bool) ScheduleRule {
+	rule := NewTimeScheduleRule(0, 0, days, on)
+	rule.StartType = trigger
+	rule.TimeOffset = offsetMinutes
+	return rule
+}
+
+// Validate checks the rule against the limits the device accepts.
+func (r ScheduleRule) Validate() error {
+	switch r.StartType {
+	case ScheduleTriggerTime:
+		if r.StartMinute < 0 || r.StartMinute >= 24*60 {
+			return fmt.Errorf("start minute %d is outside 0-1439", r.StartMinute)
+		}
+	case ScheduleTriggerSunrise, ScheduleTriggerSunset:
+		if r.TimeOffset < -maxSunOffsetMinutes || r.TimeOffset > maxSunOffsetMinutes {
+			return fmt.Errorf("offset %d is outside %d-%d minutes", r.TimeOffset, -maxSunOffsetMinutes, maxSunOffsetMinutes)
+		}
+	default:
+		return fmt.Errorf("unsupported schedule trigger: %q", r.StartType)
+	}
+	switch r.Mode {
+	case ScheduleModeRepeat:
+		if r.WeekDays == 0 || r.WeekDays > EveryDay {
+			return fmt.Errorf("invalid weekday mask: %#x", r.WeekDays)
+		}
+	case ScheduleModeOnce:
+		if r.Year == 0 || r.Month == 0 || r.Day == 0 {
+			return fmt.Errorf("a rule that runs once needs a date")
+		}
+	default:
+		return fmt.Errorf("unsupported schedule mode: %q", r.Mode)
+	}
+	return nil
+}
+
+func (d *Device) getScheduleRules(ctx context.Context) (*ScheduleRules, error) {
+	rules := &ScheduleRules{Rules: make([]ScheduleRule, 0)}
+	for {
+		var page scheduleRulesPage
+		params := map[string]int{"start_index": len(rules.Rules)}
+		if err := d.executeSingleMethod(ctx, "get_schedule_rules", params, &page); err != nil {
+			return nil, err
+		}
+		rules.Enable = page.Enable
+		rules.MaxCount = page.ScheduleRuleMaxCount
+		rules.Rules = append(rules.Rules, page.RuleList...)
+		if len(page.RuleList) == 0 || len(rules.Rules) >= page.Sum {
+			return rules, nil
+		}
+	}
+}
+
+func (d *Device) addScheduleRule(ctx context.Context, rule ScheduleRule) (string, error) {
+	if err := rule.Validate(); err != nil {
+		return "", err
+	}
+	rule.Id = ""
+	var response struct {
+		Id string `json:"id"`
+	}
+	if err := d.executeSingleMethod(ctx, "add_schedule_rule", rule, &response); err != nil {
+		return "", err
+	}
+	return response.Id, nil
+}
+
+func (d *Device) editScheduleRule(ctx context.Context, rule ScheduleRule) error {
+	if rule.Id == "" {
+		return fmt.Errorf("rule id must not be empty")
+	}
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	return d.executeSingleMethod(ctx, "edit_schedule_rule", rule, nil)
+}
+
+// removeRules removes the rules with the given ids, or every rule if no ids are given,
+// through one of the remove_*_rules methods.
+func (d *Device) removeRules(ctx context.Context, method string, ids []string) error {
+	params := map[string]any{"remove_all": len(ids) == 0}
+	if len(ids) > 0 {
+		ruleList := make([]map[string]string, 0, len(ids))
+		for _, id := range ids {
+			ruleList = append(ruleList, map[string]string{"id": id})
+		}
+		params["rule_list"] = ruleList
+	}
+	return d.executeSingleMethod(ctx, method, params, nil)
+}
+
+// GetScheduleRules returns every schedule rule stored on the plug.
+func (t *SmartPlug) GetScheduleRules(ctx context.Context) (*ScheduleRules, error) {
+	return t.getScheduleRules(ctx)
+}
+
+// AddScheduleRule stores a new schedule rule on the plug and returns its id.
+func (t *SmartPlug) AddScheduleRule(ctx context.Context, rule ScheduleRule) (string, error) {
+	return t.addScheduleRule(ctx, rule)
+}
+
+func (t *SmartPlug) EditScheduleRule(ctx context.Context, rule ScheduleRule) error {
+	return t.editScheduleRule(ctx, rule)
+}
+
+// RemoveScheduleRules removes the schedule rules with the given ids, or all of them if no ids are given.
+func (t *SmartPlug) RemoveScheduleRules(ctx context.Context, ids ...string) error {
+	return t.removeRules(ctx, "remove_schedule_rules", ids)
+}
+
+// GetScheduleRules returns every schedule rule stored on the light.
+func (b *SmartBulb) GetScheduleRules(ctx context.Context) (*ScheduleRules, error) {
+	return b.getScheduleRules(ctx)
+}
+
+// AddScheduleRule stores a new schedule rule on the light and returns its id.
+func (b *SmartBulb) AddScheduleRule(ctx context.Context, rule ScheduleRule) (string, error) {
+	return b.addScheduleRule(ctx, rule)
+}
+
+func (b *SmartBulb) EditScheduleRule(ctx context.Context, rule ScheduleRule) error {
+	return b.editScheduleRule(ctx, rule)
+}
+
+// RemoveScheduleRules removes the schedule rules with the given ids, or all of them if no ids are given.
+func (b *SmartBulb) RemoveScheduleRules(ctx context.Context, ids ...string) error {
+	return b.removeRules(ctx, "remove_schedule_rules", ids)
+}
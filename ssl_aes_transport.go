@@ -237,6 +237,16 @@ func (t *SslAesTransport) handshake2(ctx context.Context) (*Handshake2Response,
 	return &responseBody, nil
 }
 
+func (t *SslAesTransport) reconnect(ctx context.Context) error {
+	transport, err := handshake(ctx, t.host, t.username, t.password, t.httpClient)
+	if err != nil {
+		return err
+	}
+	transport.retryConfig = t.retryConfig
+	*t = *transport
+	return nil
+}
+
 func (t *SslAesTransport) ExecuteRequest(ctx context.Context, request *RequestSpec) (json.RawMessage, error) {
 	return ExecuteHttpRequest(ctx, t, request, t.retryConfig)
 }
@@ -0,0 +1,148 @@
+package tapo
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SslTransport is the transport used by Tapo robot vacuums. Requests are sent as plain JSON over TLS
+// to the device's HTTPS port, authenticated by a token obtained with a login request.
+type SslTransport struct {
+	Username    string
+	Password    string
+	Host        string
+	httpClient  *http.Client
+	retryConfig *RetryConfig
+
+	token string
+}
+
+var defaultSslHttpTransport = &http.Transport{
+	TLSClientConfig: &tls.Config{
+		InsecureSkipVerify: true,
+	},
+}
+
+type sslLoginRequest struct {
+	Method string `json:"method"`
+	Params struct {
+		Hashed   bool   `json:"hashed"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"params"`
+}
+
+type sslLoginResponse struct {
+	ErrorCode int `json:"error_code"`
+	Result    struct {
+		Token string `json:"token"`
+	} `json:"result"`
+}
+
+func NewSslTransport(ctx context.Context, username, password, host string, options Options) (*SslTransport, error) {
+	client := options.HttpClient
+	if client == nil {
+		client = &http.Client{Transport: defaultSslHttpTransport}
+	}
+
+	if !strings.Contains(host, ":") {
+		host = host + ":4433"
+	}
+
+	tr := &SslTransport{
+		Username:    username,
+		Password:    password,
+		Host:        host,
+		httpClient:  client,
+		retryConfig: options.RetryConfig,
+	}
+	err := tr.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+func (s *SslTransport) login(ctx context.Context) error {
+	passwordHash := md5.Sum([]byte(s.Password))
+	request := sslLoginRequest{Method: "login"}
+	request.Params.Hashed = true
+	request.Params.Username = s.Username
+	request.Params.Password = strings.ToUpper(hex.EncodeToString(passwordHash[:]))
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	responseBody, statusCode, err := s.post(ctx, fmt.Sprintf("https://%s/app", s.Host), body)
+	if err != nil {
+		return fmt.Errorf("error making login request: %s", err)
+	}
+	if statusCode != 200 {
+		return fmt.Errorf("login failed with status code: %d, response: %s", statusCode, responseBody)
+	}
+
+	var response sslLoginResponse
+	if err = json.Unmarshal(responseBody, &response); err != nil {
+		return err
+	}
+	if response.ErrorCode != 0 || response.Result.Token == "" {
+		return fmt.Errorf("login failed with error code: %d", response.ErrorCode)
+	}
+	s.token = response.Result.Token
+	return nil
+}
+
+func (s *SslTransport) reconnect(ctx context.Context) error {
+	return s.login(ctx)
+}
+
+func (s *SslTransport) ExecuteRequest(ctx context.Context, request *RequestSpec) (response json.RawMessage, err error) {
+	return ExecuteHttpRequest(ctx, s, request, s.retryConfig)
+}
+
+func (s *SslTransport) executeHttpRequest(ctx context.Context, request *RequestSpec) ([]byte, int, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, -1, err
+	}
+	u := fmt.Sprintf("https://%s/app?token=%s", s.Host, url.QueryEscape(s.token))
+	responseBody, statusCode, err := s.post(ctx, u, body)
+	if err != nil {
+		return nil, -1, err
+	}
+	if statusCode != 200 {
+		return responseBody, statusCode, errors.New(fmt.Sprintf("request exited with failed status: %d", statusCode))
+	}
+	return responseBody, statusCode, nil
+}
+
+func (s *SslTransport) post(ctx context.Context, u string, body []byte) ([]byte, int, error) {
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, -1, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	httpResponse, err := s.httpClient.Do(httpRequest)
+	if err != nil {
+		return nil, -1, err
+	}
+	defer httpResponse.Body.Close()
+
+	responseBody, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return nil, -1, err
+	}
+	return responseBody, httpResponse.StatusCode, nil
+}
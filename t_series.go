@@ -2,9 +2,10 @@ package tapo
 
 import (
 	"context"
-	"encoding/json"
+	"encoding/base64"
 	"errors"
-	"strconv"
+	"fmt"
+	"time"
 )
 
 type TSeries struct {
@@ -15,59 +16,416 @@ func NewTSeriesDevices(hub *Hub) *TSeries {
 	return &TSeries{hub}
 }
 
+// GetTSeriesDevices returns the T310 and T315 temperature and humidity sensors attached to the hub.
 func (t *TSeries) GetTSeriesDevices(ctx context.Context) ([]TSeriesResponse, error) {
-	devices, err := t.hub.GetChildDevices(ctx)
+	devices, err := t.hub.GetChildDeviceList(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if devices.ErrorCode != 0 {
-		return nil, errors.New("error getting devices, error code: " + strconv.Itoa(devices.ErrorCode))
-	}
 	deviceList := make([]TSeriesResponse, 0)
-	for _, d := range devices.Result.Responses {
-		var childDeviceList []byte
-		childDeviceList, err = d.Result.ChildDeviceList.MarshalJSON()
-		if err != nil {
-			return nil, err
-		}
-		var response []TSeriesResponse
-		err = json.Unmarshal(childDeviceList, &response)
-		if err != nil {
-			return nil, err
+	for _, d := range devices {
+		if sensor, ok := d.(*TSeriesResponse); ok {
+			deviceList = append(deviceList, *sensor)
 		}
-		deviceList = append(deviceList, response...)
 	}
-	return deviceList, err
+	return deviceList, nil
 }
 
 type TSeriesResponse struct {
-	ParentDeviceId           string  `json:"parent_device_id"`
-	HwVer                    string  `json:"hw_ver"`
-	FwVer                    string  `json:"fw_ver"`
-	DeviceId                 string  `json:"device_id"`
-	Mac                      string  `json:"mac"`
-	Type                     string  `json:"type"`
-	Model                    string  `json:"model"`
-	HwId                     string  `json:"hw_id"`
-	OemId                    string  `json:"oem_id"`
-	Specs                    string  `json:"specs"`
-	Category                 string  `json:"category"`
-	BindCount                int     `json:"bind_count"`
-	StatusFollowEdge         bool    `json:"status_follow_edge"`
-	Status                   string  `json:"status"`
-	LastOnboardingTimestamp  int     `json:"lastOnboardingTimestamp"`
-	Rssi                     int     `json:"rssi"`
-	SignalLevel              int     `json:"signal_level"`
-	JammingRssi              int     `json:"jamming_rssi"`
-	JammingSignalLevel       int     `json:"jamming_signal_level"`
-	AtLowBattery             bool    `json:"at_low_battery"`
+	HubChildInfo
 	TempUnit                 string  `json:"temp_unit"`
 	CurrentTemp              float64 `json:"current_temp"`
 	CurrentHumidity          int     `json:"current_humidity"`
 	CurrentTempException     float64 `json:"current_temp_exception"`
 	CurrentHumidityException int     `json:"current_humidity_exception"`
-	Nickname                 string  `json:"nickname"`
-	Avatar                   string  `json:"avatar"`
 	ReportInterval           int     `json:"report_interval"`
-	Region                   string  `json:"region"`
+}
+
+// HubChildInfo holds the fields that every device attached to a hub reports in get_device_info.
+type HubChildInfo struct {
+	ParentDeviceId          string `json:"parent_device_id"`
+	HwVer                   string `json:"hw_ver"`
+	FwVer                   string `json:"fw_ver"`
+	DeviceId                string `json:"device_id"`
+	Mac                     string `json:"mac"`
+	Type                    string `json:"type"`
+	Model                   string `json:"model"`
+	HwId                    string `json:"hw_id"`
+	OemId                   string `json:"oem_id"`
+	Specs                   string `json:"specs"`
+	Category                string `json:"category"`
+	BindCount               int    `json:"bind_count"`
+	StatusFollowEdge        bool   `json:"status_follow_edge"`
+	Status                  string `json:"status"`
+	LastOnboardingTimestamp int    `json:"lastOnboardingTimestamp"`
+	Rssi                    int    `json:"rssi"`
+	SignalLevel             int    `json:"signal_level"`
+	JammingRssi             int    `json:"jamming_rssi"`
+	JammingSignalLevel      int    `json:"jamming_signal_level"`
+	AtLowBattery            bool   `json:"at_low_battery"`
+	Nickname                string `json:"nickname"`
+	Avatar                  string `json:"avatar"`
+	Region                  string `json:"region"`
+}
+
+// MotionSensitivity is the detection sensitivity of a T100 motion sensor.
+type MotionSensitivity string
+
+const (
+	MotionSensitivityHigh   MotionSensitivity = "high"
+	MotionSensitivityNormal MotionSensitivity = "normal"
+	MotionSensitivityLow    MotionSensitivity = "low"
+)
+
+// MotionSensor is a T100 motion sensor attached to a hub.
+type MotionSensor struct {
+	hub      *Hub
+	deviceId string
+}
+
+func NewMotionSensor(hub *Hub, deviceId string) *MotionSensor {
+	return &MotionSensor{hub: hub, deviceId: deviceId}
+}
+
+type MotionSensorInfo struct {
+	HubChildInfo
+	Detected    bool              `json:"detected"`
+	Sensitivity MotionSensitivity `json:"sensitivity"`
+}
+
+func (m *MotionSensor) DeviceId() string {
+	return m.deviceId
+}
+
+func (m *MotionSensor) GetDeviceInfo(ctx context.Context) (*MotionSensorInfo, error) {
+	var response MotionSensorInfo
+	err := m.hub.ExecuteChildMethod(ctx, m.deviceId, "get_device_info", nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// IsDetected reports whether the sensor currently detects motion.
+func (m *MotionSensor) IsDetected(ctx context.Context) (bool, error) {
+	info, err := m.GetDeviceInfo(ctx)
+	if err != nil {
+		return false, err
+	}
+	return info.Detected, nil
+}
+
+func (m *MotionSensor) SetSensitivity(ctx context.Context, sensitivity MotionSensitivity) error {
+	switch sensitivity {
+	case MotionSensitivityHigh, MotionSensitivityNormal, MotionSensitivityLow:
+	default:
+		return fmt.Errorf("unsupported motion sensitivity: %q", sensitivity)
+	}
+	params := map[string]MotionSensitivity{"sensitivity": sensitivity}
+	return m.hub.ExecuteChildMethod(ctx, m.deviceId, "set_device_info", params, nil)
+}
+
+// GetTriggerLogs returns up to pageSize motion events, newest first, starting from startId.
+func (m *MotionSensor) GetTriggerLogs(ctx context.Context, pageSize, startId int) (*TriggerLogsResponse, error) {
+	return m.hub.GetTriggerLogs(ctx, m.deviceId, pageSize, startId)
+}
+
+// WaterLeakStatus is the leak state reported by a T300 water leak sensor.
+type WaterLeakStatus string
+
+const (
+	WaterLeakStatusNormal WaterLeakStatus = "normal"
+	WaterLeakStatusLeak   WaterLeakStatus = "water_leak"
+	WaterLeakStatusDry    WaterLeakStatus = "water_dry"
+)
+
+// LeakSensor is a T300 water leak sensor attached to a hub.
+type LeakSensor struct {
+	hub      *Hub
+	deviceId string
+}
+
+func NewLeakSensor(hub *Hub, deviceId string) *LeakSensor {
+	return &LeakSensor{hub: hub, deviceId: deviceId}
+}
+
+type LeakSensorInfo struct {
+	HubChildInfo
+	WaterLeakStatus WaterLeakStatus `json:"water_leak_status"`
+	InAlarm         bool            `json:"in_alarm"`
+}
+
+func (l *LeakSensor) DeviceId() string {
+	return l.deviceId
+}
+
+func (l *LeakSensor) GetDeviceInfo(ctx context.Context) (*LeakSensorInfo, error) {
+	var response LeakSensorInfo
+	err := l.hub.ExecuteChildMethod(ctx, l.deviceId, "get_device_info", nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ClearAlarm acknowledges an active leak alarm so the sensor stops alerting.
+func (l *LeakSensor) ClearAlarm(ctx context.Context) error {
+	params := map[string]bool{"in_alarm": false}
+	return l.hub.ExecuteChildMethod(ctx, l.deviceId, "set_device_info", params, nil)
+}
+
+// GetTriggerLogs returns up to pageSize leak events, newest first, starting from startId.
+func (l *LeakSensor) GetTriggerLogs(ctx context.Context, pageSize, startId int) (*TriggerLogsResponse, error) {
+	return l.hub.GetTriggerLogs(ctx, l.deviceId, pageSize, startId)
+}
+
+// TemperatureUnit is the unit a T31x sensor reports and displays temperatures in.
+type TemperatureUnit string
+
+const (
+	TemperatureUnitCelsius    TemperatureUnit = "celsius"
+	TemperatureUnitFahrenheit TemperatureUnit = "fahrenheit"
+)
+
+// temperatureHumidityRecordInterval is the length of a slot in the hub history, which holds
+// 96 slots covering the past 24 hours.
+const temperatureHumidityRecordInterval = 15 * time.Minute
+
+// missingRecordValue marks a slot in the hub history for which the sensor sent no reading.
+const missingRecordValue = -1000
+
+// TemperatureHumiditySensor is a T310 or T315 temperature and humidity sensor attached to a hub.
+type TemperatureHumiditySensor struct {
+	hub      *Hub
+	deviceId string
+}
+
+func NewTemperatureHumiditySensor(hub *Hub, deviceId string) *TemperatureHumiditySensor {
+	return &TemperatureHumiditySensor{hub: hub, deviceId: deviceId}
+}
+
+func (s *TemperatureHumiditySensor) DeviceId() string {
+	return s.deviceId
+}
+
+func (s *TemperatureHumiditySensor) GetDeviceInfo(ctx context.Context) (*TSeriesResponse, error) {
+	var response TSeriesResponse
+	err := s.hub.ExecuteChildMethod(ctx, s.deviceId, "get_device_info", nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+type TemperatureHumidityRecordsResponse struct {
+	LocalTime                int64           `json:"local_time"`
+	Past24hTemp              []int           `json:"past24h_temp"`
+	Past24hTempException     []int           `json:"past24h_temp_exception"`
+	Past24hHumidity          []int           `json:"past24h_humidity"`
+	Past24hHumidityException []int           `json:"past24h_humidity_exception"`
+	TempUnit                 TemperatureUnit `json:"temp_unit"`
+}
+
+// TemperatureHumidityRecord is a single point of the sensor history.
+// Valid is false when the sensor sent no reading for that slot.
+type TemperatureHumidityRecord struct {
+	Time        time.Time
+	Temperature float64
+	Unit        TemperatureUnit
+	Humidity    int
+	Valid       bool
+}
+
+type TemperatureHumidityRecords struct {
+	Unit     TemperatureUnit
+	Interval time.Duration
+	Records  []TemperatureHumidityRecord
+}
+
+// Celsius returns the record temperature converted to degrees Celsius.
+func (r TemperatureHumidityRecord) Celsius() float64 {
+	if r.Unit == TemperatureUnitFahrenheit {
+		return (r.Temperature - 32) * 5 / 9
+	}
+	return r.Temperature
+}
+
+// GetRawTemperatureHumidityRecords returns the past 24 hours of history exactly as the hub stores it.
+func (s *TemperatureHumiditySensor) GetRawTemperatureHumidityRecords(ctx context.Context) (*TemperatureHumidityRecordsResponse, error) {
+	var response TemperatureHumidityRecordsResponse
+	err := s.hub.ExecuteChildMethod(ctx, s.deviceId, "get_temp_humidity_records", nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetTemperatureHumidityRecords returns the past 24 hours of history as a time series, oldest first.
+// Temperatures are in the unit reported by the sensor.
+func (s *TemperatureHumiditySensor) GetTemperatureHumidityRecords(ctx context.Context) (*TemperatureHumidityRecords, error) {
+	response, err := s.GetRawTemperatureHumidityRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return response.Records(), nil
+}
+
+// Records converts the raw hub history into a time series. The hub keeps one slot per
+// temperatureHumidityRecordInterval, the last one covering the interval that contains local_time,
+// so the slots are timed from the end even when the hub returns fewer than 96 of them.
+func (r *TemperatureHumidityRecordsResponse) Records() *TemperatureHumidityRecords {
+	count := max(len(r.Past24hTemp), len(r.Past24hHumidity))
+	records := &TemperatureHumidityRecords{
+		Unit:     r.TempUnit,
+		Interval: temperatureHumidityRecordInterval,
+		Records:  make([]TemperatureHumidityRecord, 0, count),
+	}
+	if count == 0 {
+		return records
+	}
+	last := time.Unix(r.LocalTime, 0).Truncate(records.Interval)
+	for i := 0; i < count; i++ {
+		record := TemperatureHumidityRecord{
+			Time:  last.Add(-time.Duration(count-1-i) * records.Interval),
+			Unit:  r.TempUnit,
+			Valid: true,
+		}
+		if i < len(r.Past24hTemp) && r.Past24hTemp[i] != missingRecordValue {
+			record.Temperature = float64(r.Past24hTemp[i]) / 10
+		} else {
+			record.Valid = false
+		}
+		if i < len(r.Past24hHumidity) && r.Past24hHumidity[i] != missingRecordValue {
+			record.Humidity = r.Past24hHumidity[i]
+		} else {
+			record.Valid = false
+		}
+		records.Records = append(records.Records, record)
+	}
+	return records
+}
+
+// ComfortTemperatureConfig is the temperature range the sensor considers comfortable.
+// MinControlTemp and MaxControlTemp are the limits the sensor accepts for that range, in TempUnit,
+// and are nil if the sensor does not report them.
+type ComfortTemperatureConfig struct {
+	MinTemp        float64         `json:"min_temp"`
+	MaxTemp        float64         `json:"max_temp"`
+	TempUnit       TemperatureUnit `json:"temp_unit"`
+	MinControlTemp *float64        `json:"min_control_temp,omitempty"`
+	MaxControlTemp *float64        `json:"max_control_temp,omitempty"`
+}
+
+// ComfortHumidityConfig is the relative humidity range, in percent, the sensor considers comfortable.
+// MinControlHumidity and MaxControlHumidity are the limits the sensor accepts for that range,
+// and are nil if the sensor does not report them.
+type ComfortHumidityConfig struct {
+	MinHumidity        int  `json:"min_humidity"`
+	MaxHumidity        int  `json:"max_humidity"`
+	MinControlHumidity *int `json:"min_control_humidity,omitempty"`
+	MaxControlHumidity *int `json:"max_control_humidity,omitempty"`
+}
+
+// encodeNickname encodes a nickname the way child devices expect it in set_device_info.
+func encodeNickname(nickname string) string {
+	return base64.StdEncoding.EncodeToString([]byte(nickname))
+}
+
+// DecodedNickname returns the nickname as plain text. Child devices report it base64 encoded.
+func (c HubChildInfo) DecodedNickname() string {
+	return decodeNickname(c.Nickname)
+}
+
+func decodeNickname(nickname string) string {
+	decoded, err := base64.StdEncoding.DecodeString(nickname)
+	if err != nil {
+		return nickname
+	}
+	return string(decoded)
+}
+
+func (s *TemperatureHumiditySensor) SetNickname(ctx context.Context, nickname string) error {
+	if nickname == "" {
+		return errors.New("nickname must not be empty")
+	}
+	params := map[string]string{"nickname": encodeNickname(nickname)}
+	return s.hub.ExecuteChildMethod(ctx, s.deviceId, "set_device_info", params, nil)
+}
+
+func (s *TemperatureHumiditySensor) SetTemperatureUnit(ctx context.Context, unit TemperatureUnit) error {
+	if unit != TemperatureUnitCelsius && unit != TemperatureUnitFahrenheit {
+		return fmt.Errorf("unsupported temperature unit: %q", unit)
+	}
+	params := map[string]TemperatureUnit{"temp_unit": unit}
+	return s.hub.ExecuteChildMethod(ctx, s.deviceId, "set_device_info", params, nil)
+}
+
+func (s *TemperatureHumiditySensor) GetComfortTemperature(ctx context.Context) (*ComfortTemperatureConfig, error) {
+	var response ComfortTemperatureConfig
+	err := s.hub.ExecuteChildMethod(ctx, s.deviceId, "get_comfort_temp_config", nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SetComfortTemperature sets the temperature range the sensor considers comfortable.
+// The values are in the unit the sensor currently reports and must lie within the limits it reports.
+func (s *TemperatureHumiditySensor) SetComfortTemperature(ctx context.Context, minTemp, maxTemp float64) error {
+	config, err := s.GetComfortTemperature(ctx)
+	if err != nil {
+		return err
+	}
+	if minTemp >= maxTemp {
+		return fmt.Errorf("comfort temperature range %.1f-%.1f is empty", minTemp, maxTemp)
+	}
+	if config.MinControlTemp == nil || config.MaxControlTemp == nil {
+		return errors.New("device reported no comfort temperature limits")
+	}
+	if minTemp < *config.MinControlTemp || maxTemp > *config.MaxControlTemp {
+		return fmt.Errorf("comfort temperature range %.1f-%.1f is outside %.1f-%.1f %s",
+			minTemp, maxTemp, *config.MinControlTemp, *config.MaxControlTemp, config.TempUnit)
+	}
+	params := map[string]any{"min_temp": minTemp, "max_temp": maxTemp, "temp_unit": config.TempUnit}
+	return s.hub.ExecuteChildMethod(ctx, s.deviceId, "set_comfort_temp_config", params, nil)
+}
+
+func (s *TemperatureHumiditySensor) GetComfortHumidity(ctx context.Context) (*ComfortHumidityConfig, error) {
+	var response ComfortHumidityConfig
+	err := s.hub.ExecuteChildMethod(ctx, s.deviceId, "get_comfort_humidity_config", nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SetComfortHumidity sets the relative humidity range, in percent, the sensor considers comfortable.
+// The values must lie within the limits the sensor reports.
+func (s *TemperatureHumiditySensor) SetComfortHumidity(ctx context.Context, minHumidity, maxHumidity int) error {
+	config, err := s.GetComfortHumidity(ctx)
+	if err != nil {
+		return err
+	}
+	if minHumidity >= maxHumidity {
+		return fmt.Errorf("comfort humidity range %d-%d is empty", minHumidity, maxHumidity)
+	}
+	if config.MinControlHumidity == nil || config.MaxControlHumidity == nil {
+		return errors.New("device reported no comfort humidity limits")
+	}
+	if minHumidity < *config.MinControlHumidity || maxHumidity > *config.MaxControlHumidity {
+		return fmt.Errorf("comfort humidity range %d-%d is outside %d-%d",
+			minHumidity, maxHumidity, *config.MinControlHumidity, *config.MaxControlHumidity)
+	}
+	params := map[string]int{"min_humidity": minHumidity, "max_humidity": maxHumidity}
+	return s.hub.ExecuteChildMethod(ctx, s.deviceId, "set_comfort_humidity_config", params, nil)
+}
+
+// SetReportInterval sets how often the sensor reports its readings to the hub.
+// The sensor takes the interval in whole seconds and reports no upper limit for it.
+func (s *TemperatureHumiditySensor) SetReportInterval(ctx context.Context, interval time.Duration) error {
+	if interval < time.Second || interval%time.Second != 0 {
+		return fmt.Errorf("report interval %s must be a positive whole number of seconds", interval)
+	}
+	params := map[string]int{"report_interval": int(interval / time.Second)}
+	return s.hub.ExecuteChildMethod(ctx, s.deviceId, "set_device_info", params, nil)
 }
@@ -0,0 +1,135 @@
+package tapo
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTemperatureHumidityRecords(t *testing.T) {
+	// 14:07:30 UTC falls into the slot starting at 14:00.
+	localTime := time.Date(2024, time.March, 10, 14, 7, 30, 0, time.UTC)
+	lastSlot := time.Date(2024, time.March, 10, 14, 0, 0, 0, time.UTC)
+	full := make([]int, 96)
+	for i := range full {
+		full[i] = 200 + i
+	}
+	fullHumidity := make([]int, 96)
+	for i := range fullHumidity {
+		fullHumidity[i] = 40
+	}
+
+	type record struct {
+		index       int
+		time        time.Time
+		temperature float64
+		humidity    int
+		valid       bool
+	}
+	tests := []struct {
+		name      string
+		response  TemperatureHumidityRecordsResponse
+		wantCount int
+		want      []record
+	}{
+		{
+			name: "full history",
+			response: TemperatureHumidityRecordsResponse{
+				LocalTime: localTime.Unix(), Past24hTemp: full, Past24hHumidity: fullHumidity, TempUnit: TemperatureUnitCelsius,
+			},
+			wantCount: 96,
+			want: []record{
+				{index: 0, time: lastSlot.Add(-95 * 15 * time.Minute), temperature: 20.0, humidity: 40, valid: true},
+				{index: 95, time: lastSlot, temperature: 29.5, humidity: 40, valid: true},
+			},
+		},
+		{
+			name: "short history is timed from the end",
+			response: TemperatureHumidityRecordsResponse{
+				LocalTime: localTime.Unix(), Past24hTemp: []int{215, 220}, Past24hHumidity: []int{50, 51}, TempUnit: TemperatureUnitCelsius,
+			},
+			wantCount: 2,
+			want: []record{
+				{index: 0, time: lastSlot.Add(-15 * time.Minute), temperature: 21.5, humidity: 50, valid: true},
+				{index: 1, time: lastSlot, temperature: 22.0, humidity: 51, valid: true},
+			},
+		},
+		{
+			name: "gaps",
+			response: TemperatureHumidityRecordsResponse{
+				LocalTime: localTime.Unix(), Past24hTemp: []int{-1000, 210, 205}, Past24hHumidity: []int{45, -1000, 47}, TempUnit: TemperatureUnitCelsius,
+			},
+			wantCount: 3,
+			want: []record{
+				{index: 0, time: lastSlot.Add(-30 * time.Minute), humidity: 45, valid: false},
+				{index: 1, time: lastSlot.Add(-15 * time.Minute), temperature: 21.0, valid: false},
+				{index: 2, time: lastSlot, temperature: 20.5, humidity: 47, valid: true},
+			},
+		},
+		{
+			name: "shorter humidity history",
+			response: TemperatureHumidityRecordsResponse{
+				LocalTime: localTime.Unix(), Past24hTemp: []int{700, 710}, Past24hHumidity: []int{30}, TempUnit: TemperatureUnitFahrenheit,
+			},
+			wantCount: 2,
+			want: []record{
+				{index: 0, time: lastSlot.Add(-15 * time.Minute), temperature: 70.0, humidity: 30, valid: true},
+				{index: 1, time: lastSlot, temperature: 71.0, valid: false},
+			},
+		},
+		{
+			name:      "empty history",
+			response:  TemperatureHumidityRecordsResponse{LocalTime: localTime.Unix(), TempUnit: TemperatureUnitCelsius},
+			wantCount: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := tt.response.Records()
+			if records.Interval != 15*time.Minute {
+				t.Errorf("Interval = %s, want 15m", records.Interval)
+			}
+			if records.Unit != tt.response.TempUnit {
+				t.Errorf("Unit = %q, want %q", records.Unit, tt.response.TempUnit)
+			}
+			if len(records.Records) != tt.wantCount {
+				t.Fatalf("got %d records, want %d", len(records.Records), tt.wantCount)
+			}
+			for _, w := range tt.want {
+				got := records.Records[w.index]
+				if !got.Time.Equal(w.time) {
+					t.Errorf("record %d: Time = %s, want %s", w.index, got.Time.UTC(), w.time)
+				}
+				if got.Valid != w.valid {
+					t.Errorf("record %d: Valid = %t, want %t", w.index, got.Valid, w.valid)
+				}
+				if got.Temperature != w.temperature || got.Humidity != w.humidity {
+					t.Errorf("record %d: got %.1f %d%%, want %.1f %d%%", w.index, got.Temperature, got.Humidity, w.temperature, w.humidity)
+				}
+				if got.Unit != tt.response.TempUnit {
+					t.Errorf("record %d: Unit = %q, want %q", w.index, got.Unit, tt.response.TempUnit)
+				}
+			}
+		})
+	}
+}
+
+func TestTemperatureHumidityRecordCelsius(t *testing.T) {
+	tests := []struct {
+		name   string
+		record TemperatureHumidityRecord
+		want   float64
+	}{
+		{name: "celsius is unchanged", record: TemperatureHumidityRecord{Temperature: 21.5, Unit: TemperatureUnitCelsius}, want: 21.5},
+		{name: "freezing point", record: TemperatureHumidityRecord{Temperature: 32, Unit: TemperatureUnitFahrenheit}, want: 0},
+		{name: "room temperature", record: TemperatureHumidityRecord{Temperature: 70.7, Unit: TemperatureUnitFahrenheit}, want: 21.5},
+		{name: "below zero", record: TemperatureHumidityRecord{Temperature: -4, Unit: TemperatureUnitFahrenheit}, want: -20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.record.Celsius(); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Celsius() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
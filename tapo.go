@@ -5,6 +5,8 @@ import (
 	"crypto/md5"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"net/http"
 	"time"
@@ -97,3 +99,82 @@ func (d *Device) ExecuteMethod(ctx context.Context, method string, params json.R
 	}
 	return json.Unmarshal(stringResponse, &result)
 }
+
+// executeMultipleRequest sends a single camelCase method wrapped in a multipleRequest, as hubs and
+// cameras on the SSL AES transport expect, and decodes the method's result into result.
+func (d *Device) executeMultipleRequest(ctx context.Context, method string, params any, result any) error {
+	body, err := json.Marshal(hubMultipleRequest{Requests: []hubRequest{{Method: method, Params: params}}})
+	if err != nil {
+		return err
+	}
+	var response hubMultipleResponse
+	if err = d.ExecuteMethod(ctx, "multipleRequest", body, &response); err != nil {
+		return err
+	}
+	if response.ErrorCode != 0 {
+		return &DeviceError{Method: method, Code: response.ErrorCode}
+	}
+	if len(response.Result.Responses) == 0 {
+		return fmt.Errorf("empty response for %s", method)
+	}
+	if response.Result.Responses[0].ErrorCode != 0 {
+		return &DeviceError{Method: method, Code: response.Result.Responses[0].ErrorCode}
+	}
+	if result == nil || len(response.Result.Responses[0].Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(response.Result.Responses[0].Result, result)
+}
+
+// singleMethodResponse is the envelope of a response to a single snake_case method.
+// DeviceError is returned when a device answers a method with a non-zero error code.
+type DeviceError struct {
+	Method string
+	Code   int
+}
+
+func (e *DeviceError) Error() string {
+	return fmt.Sprintf("%s failed with error code: %d", e.Method, e.Code)
+}
+
+type singleMethodResponse struct {
+	Result    json.RawMessage `json:"result"`
+	ErrorCode int             `json:"error_code"`
+}
+
+// executeSingleMethod sends a snake_case method directly, as devices on the KLAP and SSL transports
+// expect, and decodes its result into result.
+func (d *Device) executeSingleMethod(ctx context.Context, method string, params any, result any) error {
+	var body json.RawMessage
+	if params != nil {
+		var err error
+		if body, err = json.Marshal(params); err != nil {
+			return err
+		}
+	}
+	var response singleMethodResponse
+	if err := d.ExecuteMethod(ctx, method, body, &response); err != nil {
+		return err
+	}
+	if response.ErrorCode != 0 {
+		return &DeviceError{Method: method, Code: response.ErrorCode}
+	}
+	if result == nil || len(response.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(response.Result, result)
+}
+
+// reconnector is implemented by transports that can establish a new session,
+// for example after the device rebooted.
+type reconnector interface {
+	reconnect(ctx context.Context) error
+}
+
+func (d *Device) reconnect(ctx context.Context) error {
+	r, ok := d.transport.(reconnector)
+	if !ok {
+		return errors.New("transport does not support reconnecting")
+	}
+	return r.reconnect(ctx)
+}
@@ -0,0 +1,66 @@
+package tapo
+
+import (
+	"context"
+	"time"
+)
+
+type usagePeriods struct {
+	Today  int `json:"today"`
+	Past7  int `json:"past7"`
+	Past30 int `json:"past30"`
+}
+
+type deviceUsageResponse struct {
+	TimeUsage  usagePeriods `json:"time_usage"`
+	PowerUsage usagePeriods `json:"power_usage"`
+	SavedPower usagePeriods `json:"saved_power"`
+}
+
+// UsageTime is how long the device was on today, in the past 7 days and in the past 30 days.
+type UsageTime struct {
+	Today  time.Duration
+	Past7  time.Duration
+	Past30 time.Duration
+}
+
+// UsageEnergy is an amount of energy in Wh for today, the past 7 days and the past 30 days.
+type UsageEnergy struct {
+	Today  int
+	Past7  int
+	Past30 int
+}
+
+// DeviceUsage summarizes how long the device was on, how much energy it used and how much
+// it saved, for example by switching off on a schedule.
+type DeviceUsage struct {
+	OnTime      UsageTime
+	Energy      UsageEnergy
+	SavedEnergy UsageEnergy
+}
+
+func (d *Device) getDeviceUsage(ctx context.Context) (*DeviceUsage, error) {
+	var response deviceUsageResponse
+	if err := d.executeSingleMethod(ctx, "get_device_usage", nil, &response); err != nil {
+		return nil, err
+	}
+	return &DeviceUsage{
+		OnTime: UsageTime{
+			Today:  time.Duration(response.TimeUsage.Today) * time.Minute,
+			Past7:  time.Duration(response.TimeUsage.Past7) * time.Minute,
+			Past30: time.Duration(response.TimeUsage.Past30) * time.Minute,
+		},
+		Energy:      UsageEnergy(response.PowerUsage),
+		SavedEnergy: UsageEnergy(response.SavedPower),
+	}, nil
+}
+
+// GetDeviceUsage returns the on time and energy summaries the plug keeps.
+func (t *SmartPlug) GetDeviceUsage(ctx context.Context) (*DeviceUsage, error) {
+	return t.getDeviceUsage(ctx)
+}
+
+// GetDeviceUsage returns the on time and energy summaries the light keeps.
+func (b *SmartBulb) GetDeviceUsage(ctx context.Context) (*DeviceUsage, error) {
+	return b.getDeviceUsage(ctx)
+}